@@ -0,0 +1,293 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// writeJSON mengirim response JSON dengan status code tertentu
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, format string, args ...interface{}) {
+	writeJSON(w, status, map[string]interface{}{
+		"error": fmt.Sprintf(format, args...),
+	})
+}
+
+// parseSearchRequest membaca RequestData dari body JSON (POST) lalu menimpanya dengan query string
+func parseSearchRequest(r *http.Request) (RequestData, error) {
+	// REST dipakai untuk share-link dan test regresi, jadi hasilnya deterministic kecuali diminta lain
+	req := RequestData{Algorithm: "BFS", MaxRecipes: 1, Deterministic: true}
+
+	if r.Method == http.MethodPost && r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			return req, fmt.Errorf("invalid JSON body: %v", err)
+		}
+	}
+
+	q := r.URL.Query()
+	if v := q.Get("target"); v != "" {
+		req.Target = v
+	}
+	if v := q.Get("algorithm"); v != "" {
+		req.Algorithm = v
+	}
+	if v := q.Get("dataset"); v != "" {
+		req.Dataset = v
+	}
+	if v := q.Get("objective"); v != "" {
+		req.Objective = v
+	}
+	if v := q.Get("targets"); v != "" {
+		req.Targets = strings.Split(v, ",")
+	}
+	if v := q.Get("inventory"); v != "" {
+		req.Inventory = strings.Split(v, ",")
+	}
+	bools := []struct {
+		name string
+		dst  *bool
+	}{
+		{"sequence", &req.Sequence},
+		{"metadata", &req.Metadata},
+		{"deterministic", &req.Deterministic},
+	}
+	for _, p := range bools {
+		v := q.Get(p.name)
+		if v == "" {
+			continue
+		}
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return req, fmt.Errorf("invalid %s %q", p.name, v)
+		}
+		*p.dst = b
+	}
+
+	ints := []struct {
+		name string
+		dst  *int
+	}{
+		{"max", &req.MaxRecipes},
+		{"timeoutMs", &req.TimeoutMs},
+		{"maxNodes", &req.MaxNodes},
+		{"maxDepth", &req.MaxDepth},
+		{"maxQueue", &req.MaxQueue},
+	}
+	for _, p := range ints {
+		v := q.Get(p.name)
+		if v == "" {
+			continue
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return req, fmt.Errorf("invalid %s %q", p.name, v)
+		}
+		*p.dst = n
+	}
+	if v := q.Get("seed"); v != "" {
+		seed, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return req, fmt.Errorf("invalid seed %q", v)
+		}
+		req.Seed = seed
+	}
+
+	objective, err := parseObjective(req.Objective)
+	if err != nil {
+		return req, err
+	}
+	req.Objective = objective
+
+	if strings.TrimSpace(req.Target) == "" && len(req.Targets) == 0 {
+		return req, fmt.Errorf("missing target")
+	}
+	return req, nil
+}
+
+// checkSearchRequest mencari solver dan target, atau menulis response error jika tidak valid
+func checkSearchRequest(w http.ResponseWriter, graph *RecipeGraph, req RequestData) (Solver, string, bool) {
+	solver, ok := lookupSolver(req.Algorithm)
+	if !ok {
+		writeJSON(w, http.StatusBadRequest, map[string]interface{}{
+			"error":      fmt.Sprintf("Unknown algorithm %q", req.Algorithm),
+			"algorithms": solverNames(),
+		})
+		return nil, "", false
+	}
+
+	target, ok := graph.Resolve(req.Target)
+	if !ok {
+		writeJSON(w, http.StatusNotFound, map[string]interface{}{
+			"error":       fmt.Sprintf("Unknown element %q", req.Target),
+			"suggestions": graph.suggest(target, 5),
+		})
+		return nil, "", false
+	}
+	return solver, target, true
+}
+
+// checkPlanTargets menormalkan req.Targets, atau menulis response error jika ada elemen yang tidak dikenal
+func checkPlanTargets(w http.ResponseWriter, graph *RecipeGraph, req RequestData) ([]string, bool) {
+	if req.Target != "" {
+		req.Targets = append([]string{req.Target}, req.Targets...)
+	}
+	targets, unknown := graph.normalizeTargets(req.Targets)
+	if len(unknown) > 0 {
+		suggestions := make(map[string][]ElementSummary, len(unknown))
+		for _, t := range unknown {
+			suggestions[t] = graph.suggest(t, 5)
+		}
+		writeJSON(w, http.StatusNotFound, map[string]interface{}{
+			"error":       fmt.Sprintf("Unknown elements %q", unknown),
+			"suggestions": suggestions,
+		})
+		return nil, false
+	}
+	return targets, true
+}
+
+// handleSearchAPI adalah versi REST dari /ws: GET/POST /api/search?target=...&algorithm=...&max=...
+func handleSearchAPI(datasets *Datasets, w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	req, err := parseSearchRequest(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "%v", err)
+		return
+	}
+
+	graph, ok := datasets.requestGraph(w, req.Dataset)
+	if !ok {
+		return
+	}
+
+	if len(req.Targets) > 0 {
+		handlePlanAPI(graph, w, r, req)
+		return
+	}
+
+	solver, target, ok := checkSearchRequest(w, graph, req)
+	if !ok {
+		return
+	}
+
+	opts := SolverOptions{
+		MaxRecipes:    req.recipeLimit(),
+		Limits:        req.limits(),
+		Objective:     req.Objective,
+		Inventory:     req.Inventory,
+		Deterministic: req.Deterministic,
+		Seed:          req.Seed,
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), opts.Limits.Timeout)
+	defer cancel()
+
+	startTime := time.Now()
+	result := solver.Solve(ctx, graph, target, opts)
+	elapsed := time.Since(startTime)
+
+	log.Printf("API search - Element: %s, Algorithm: %s, found %d recipes in %v\n",
+		target, solver.Name(), len(result.Trees), elapsed)
+
+	if result.Trees == nil {
+		result.Trees = []TreeNode{}
+	}
+
+	status := http.StatusOK
+	message := fmt.Sprintf("Found %d recipe plans", len(result.Trees))
+	if len(result.Trees) == 0 {
+		message = "No recipe plans found"
+		if result.TruncatedBy == truncatedByTimeout {
+			status = http.StatusGatewayTimeout
+			message = "Search timed out before any recipe was found"
+		}
+	}
+
+	response := map[string]interface{}{
+		"status":         "Completed",
+		"message":        message,
+		"target":         graph.displayName(target),
+		"algorithm":      solver.Name(),
+		"totalRecipes":   formatCount(graph.RecipeCount(target)),
+		"duration":       formatTime(elapsed.String()),
+		"treeData":       result.Trees,
+		"nodes":          result.NodesVisited,
+		"truncatedBy":    result.TruncatedBy,
+		"datasetVersion": graph.Version,
+		"deterministic":  opts.order().deterministic,
+	}
+	if opts.Seed != 0 {
+		response["seed"] = opts.Seed
+	}
+	if req.Sequence {
+		response["sequences"] = graph.treeSequences(result.Trees)
+	}
+	if req.Metadata {
+		response["treeData"] = graph.withMetadata(result.Trees)
+	}
+	writeJSON(w, status, response)
+}
+
+// handlePlanAPI menangani /api/search?targets=a,b: satu rencana gabungan untuk semua target
+func handlePlanAPI(graph *RecipeGraph, w http.ResponseWriter, r *http.Request, req RequestData) {
+	targets, ok := checkPlanTargets(w, graph, req)
+	if !ok {
+		return
+	}
+
+	opts := SolverOptions{Limits: req.limits(), Inventory: req.Inventory}
+	ctx, cancel := context.WithTimeout(r.Context(), opts.Limits.Timeout)
+	defer cancel()
+
+	startTime := time.Now()
+	plan := planTargets(ctx, graph, targets, opts)
+	elapsed := time.Since(startTime)
+
+	log.Printf("API plan - Elements: %v, %d combinations in %v\n", targets, len(plan.Steps), elapsed)
+
+	status := http.StatusOK
+	if !plan.Found && plan.TruncatedBy == truncatedByTimeout {
+		status = http.StatusGatewayTimeout
+	}
+	response := map[string]interface{}{
+		"status":         "Completed",
+		"message":        planMessage(plan, targets),
+		"targets":        graph.displayNames(targets),
+		"steps":          graph.displaySteps(plan.Steps),
+		"treeData":       plan.Trees,
+		"duration":       formatTime(elapsed.String()),
+		"nodes":          plan.NodesVisited,
+		"truncatedBy":    plan.TruncatedBy,
+		"datasetVersion": graph.Version,
+	}
+	if req.Sequence {
+		response["sequences"] = [][]CraftStep{graph.craftSequence(plan.Steps)}
+	}
+	if req.Metadata {
+		response["treeData"] = graph.withMetadata(plan.Trees)
+	}
+	writeJSON(w, status, response)
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newTestDatasets menyimpan testElements ke folder sementara dan memuatnya sebagai dataset default
+func newTestDatasets(t *testing.T) *Datasets {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "elements.json")
+	writeTestDataset(t, path, testElements)
+	datasets, err := newDatasets(gameLA2, map[string]string{gameLA2: path}, true, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return datasets
+}
+
+// TestSearchRequestRecipeLimit memastikan max=0 dan maxRecipes yang tidak dikirim sampai ke solver sebagai 1
+func TestSearchRequestRecipeLimit(t *testing.T) {
+	tests := []struct {
+		url  string
+		want int
+	}{
+		{"/api/search?target=brick", 1},
+		{"/api/search?target=brick&max=0", 1},
+		{"/api/search?target=brick&max=5", 5},
+	}
+	for _, tt := range tests {
+		req, err := parseSearchRequest(httptest.NewRequest("GET", tt.url, nil))
+		if err != nil {
+			t.Fatalf("%s: %v", tt.url, err)
+		}
+		if got := req.recipeLimit(); got != tt.want {
+			t.Errorf("%s: recipeLimit() = %d, want %d", tt.url, got, tt.want)
+		}
+	}
+
+	// Pesan WebSocket tanpa maxRecipes
+	if got := (RequestData{Target: "brick"}).recipeLimit(); got != 1 {
+		t.Errorf("empty maxRecipes: recipeLimit() = %d, want 1", got)
+	}
+}
+
+// TestSearchRequestDeterministicByDefault: share-link REST selalu deterministic kecuali dimatikan
+func TestSearchRequestDeterministicByDefault(t *testing.T) {
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{"/api/search?target=brick", true},
+		{"/api/search?target=brick&deterministic=false", false},
+	}
+	for _, tt := range tests {
+		req, err := parseSearchRequest(httptest.NewRequest("GET", tt.url, nil))
+		if err != nil {
+			t.Fatalf("%s: %v", tt.url, err)
+		}
+		if req.Deterministic != tt.want {
+			t.Errorf("%s: Deterministic = %v, want %v", tt.url, req.Deterministic, tt.want)
+		}
+	}
+}
+
+// TestSearchRequestObjective: objective yang salah ketik ditolak, bukan diam-diam dicari sebagai steps
+func TestSearchRequestObjective(t *testing.T) {
+	tests := []struct {
+		url     string
+		want    string
+		wantErr bool
+	}{
+		{"/api/search?target=brick", "", false},
+		{"/api/search?target=brick&objective=steps", objectiveSteps, false},
+		{"/api/search?target=brick&objective=Depth", objectiveDepth, false},
+		{"/api/search?target=brick&objective=stpes", "", true},
+	}
+	for _, tt := range tests {
+		req, err := parseSearchRequest(httptest.NewRequest("GET", tt.url, nil))
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: err = %v, wantErr %v", tt.url, err, tt.wantErr)
+			continue
+		}
+		if err == nil && req.Objective != tt.want {
+			t.Errorf("%s: Objective = %q, want %q", tt.url, req.Objective, tt.want)
+		}
+	}
+
+	// Body JSON POST melewati pemeriksaan yang sama
+	body := strings.NewReader(`{"target": "brick", "objective": "deep"}`)
+	if _, err := parseSearchRequest(httptest.NewRequest("POST", "/api/search", body)); err == nil {
+		t.Error("POST with objective deep: no error")
+	}
+}
+
+// TestSearchAPITargetDisplayName: target di response REST memakai nama asli dataset seperti treeData
+// dan pesan WebSocket, bukan kunci lowercase hasil Resolve
+func TestSearchAPITargetDisplayName(t *testing.T) {
+	datasets := newTestDatasets(t)
+	rec := httptest.NewRecorder()
+	handleSearchAPI(datasets, rec, httptest.NewRequest("GET", "/api/search?target=BRICK", nil))
+	if rec.Code != 200 {
+		t.Fatalf("status %d: %s", rec.Code, rec.Body)
+	}
+
+	var resp struct {
+		Target   string     `json:"target"`
+		TreeData []TreeNode `json:"treeData"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Target != "Brick" {
+		t.Errorf("target = %q, want Brick", resp.Target)
+	}
+	if len(resp.TreeData) == 0 || resp.TreeData[0].Name != resp.Target {
+		t.Errorf("treeData root %+v does not match target %q", resp.TreeData, resp.Target)
+	}
+}
+
+// TestSearchAPISequencesKey: rencana gabungan dan pencarian satu target memakai kunci "sequences"
+// dengan bentuk yang sama, jadi client tidak perlu membedakan keduanya
+func TestSearchAPISequencesKey(t *testing.T) {
+	datasets := newTestDatasets(t)
+	for _, url := range []string{
+		"/api/search?target=wall&max=2&sequence=true",
+		"/api/search?targets=wall,brick&sequence=true",
+	} {
+		rec := httptest.NewRecorder()
+		handleSearchAPI(datasets, rec, httptest.NewRequest("GET", url, nil))
+		var resp map[string]json.RawMessage
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); rec.Code != 200 || err != nil {
+			t.Fatalf("%s: status %d, %v", url, rec.Code, err)
+		}
+		if _, ok := resp["sequence"]; ok {
+			t.Errorf("%s: response still has a \"sequence\" key", url)
+		}
+		var sequences [][]CraftStep
+		if err := json.Unmarshal(resp["sequences"], &sequences); err != nil || len(sequences) == 0 || len(sequences[0]) == 0 {
+			t.Errorf("%s: sequences = %s, want a list of step lists", url, resp["sequences"])
+		}
+	}
+}
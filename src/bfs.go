@@ -1,7 +1,9 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"log"
 	"runtime"
 	"sort"
 	"strings"
@@ -15,8 +17,8 @@ import (
 // Kami menggunakan type RecipeStep yang baru untuk pencarian BFS multi-thread
 
 type RecipeStep struct {
-	Element     string
-	Ingredients []string
+	Element     string   `json:"element"`
+	Ingredients []string `json:"ingredients"`
 }
 
 type BuildQueueItem struct {
@@ -27,8 +29,14 @@ type BuildQueueItem struct {
 
 // Struktur data thread-safe untuk multithreading BFS
 type SafeQueue struct {
-	queue []BuildQueueItem
-	mutex sync.Mutex
+	queue   []BuildQueueItem
+	maxSize int
+	// inFlight adalah jumlah batch yang sudah diambil dengan Claim tetapi belum di-Release
+	inFlight int
+	// overflowed menandai queue pernah dipangkas; setelah itu popped menghitung item yang diambil
+	overflowed bool
+	popped     int
+	mutex      sync.Mutex
 }
 
 type SafeResults struct {
@@ -42,12 +50,10 @@ type SafePathKeys struct {
 	mutex sync.RWMutex
 }
 
-type Counter struct {
-	count int
-	mutex sync.Mutex
-}
-
-// Konstanta untuk pencarian
+// Konstanta untuk pencarian, dipakai jika client tidak memberi maxDepth/maxQueue.
+// Setelah queue melebihi maxQueue, BFS hanya boleh mengambil satu queue penuh lagi sebelum berhenti dengan
+// truncatedBy "maxQueue": pohon target yang dalam baru lengkap di lapisan terakhir, jadi tanpa batas ini BFS
+// terus memangkas queue sampai timeout tanpa hasil
 const (
 	bfsMaxDepth     = 500
 	bfsMaxQueueSize = 100000
@@ -55,23 +61,11 @@ const (
 	batchSize       = 50 // Ukuran batch per worker
 )
 
-// Metode untuk Counter
-func (c *Counter) Increment() {
-	c.mutex.Lock()
-	defer c.mutex.Unlock()
-	c.count++
-}
-
-func (c *Counter) Get() int {
-	c.mutex.Lock()
-	defer c.mutex.Unlock()
-	return c.count
-}
-
 // Metode untuk SafeQueue
-func newSafeQueue() *SafeQueue {
+func newSafeQueue(maxSize int) *SafeQueue {
 	return &SafeQueue{
-		queue: []BuildQueueItem{},
+		queue:   []BuildQueueItem{},
+		maxSize: maxSize,
 	}
 }
 
@@ -95,37 +89,91 @@ func (sq *SafeQueue) Pop(count int) []BuildQueueItem {
 
 	items := sq.queue[:count]
 	sq.queue = sq.queue[count:]
+	sq.countPopped(count)
+	return items
+}
+
+// Claim seperti Pop, tetapi batch yang diambil dihitung sedang diproses sampai Release dipanggil.
+// Worker yang sedang memperluas item masih bisa menambah queue, jadi queue kosong belum berarti selesai.
+func (sq *SafeQueue) Claim(count int) []BuildQueueItem {
+	sq.mutex.Lock()
+	defer sq.mutex.Unlock()
+
+	if len(sq.queue) == 0 {
+		return nil
+	}
+	count = min(count, len(sq.queue))
+	items := sq.queue[:count]
+	sq.queue = sq.queue[count:]
+	sq.inFlight++
+	sq.countPopped(count)
 	return items
 }
 
+func (sq *SafeQueue) countPopped(count int) {
+	if sq.overflowed {
+		sq.popped += count
+	}
+}
+
+// Exhausted bernilai true jika sejak queue pertama kali dipangkas sudah diambil item sebanyak satu queue
+// penuh (maxSize). Item yang tersisa setelah pemangkasan adalah yang paling dekat selesai, jadi jika
+// semuanya sudah diproses tanpa cukup hasil, pencarian sebaiknya berhenti daripada terus memangkas.
+func (sq *SafeQueue) Exhausted() bool {
+	sq.mutex.Lock()
+	defer sq.mutex.Unlock()
+	return sq.overflowed && sq.popped >= sq.maxSize
+}
+
+func (sq *SafeQueue) Release() {
+	sq.mutex.Lock()
+	defer sq.mutex.Unlock()
+	sq.inFlight--
+}
+
+// Idle bernilai true jika queue kosong dan tidak ada batch yang sedang diproses worker
+func (sq *SafeQueue) Idle() bool {
+	sq.mutex.Lock()
+	defer sq.mutex.Unlock()
+	return len(sq.queue) == 0 && sq.inFlight == 0
+}
+
 func (sq *SafeQueue) Length() int {
 	sq.mutex.Lock()
 	defer sq.mutex.Unlock()
 	return len(sq.queue)
 }
 
-func (sq *SafeQueue) PruneLarge() {
+func (sq *SafeQueue) PruneLarge() bool {
 	sq.mutex.Lock()
 	defer sq.mutex.Unlock()
 
-	if len(sq.queue) > bfsMaxQueueSize {
-		sort.Slice(sq.queue, func(i, j int) bool {
+	if len(sq.queue) > sq.maxSize {
+		sort.SliceStable(sq.queue, func(i, j int) bool {
 			return sq.queue[i].Depth < sq.queue[j].Depth
 		})
-		sq.queue = sq.queue[:bfsMaxQueueSize]
+		sq.queue = sq.queue[:sq.maxSize]
+		return true
 	}
+	return false
 }
 
-func (sq *SafeQueue) PruneLargeWithPriority() {
+// PruneLargeWithPriority membuang item paling panjang jika queue melebihi maxSize,
+// mengembalikan true jika ada item yang dibuang. Queue dipangkas sampai setengah maxSize supaya
+// pengurutan tidak diulang di setiap ekspansi selama queue tetap penuh.
+func (sq *SafeQueue) PruneLargeWithPriority() bool {
 	sq.mutex.Lock()
 	defer sq.mutex.Unlock()
 
-	if len(sq.queue) > bfsMaxQueueSize {
-		sort.Slice(sq.queue, func(i, j int) bool {
+	if len(sq.queue) > sq.maxSize {
+		sort.SliceStable(sq.queue, func(i, j int) bool {
 			return sq.queue[i].Depth+len(sq.queue[i].Open) < sq.queue[j].Depth+len(sq.queue[j].Open)
 		})
-		sq.queue = sq.queue[:bfsMaxQueueSize]
+		sq.queue = sq.queue[:max(sq.maxSize/2, 1)]
+		sq.overflowed = true
+		return true
 	}
+	return false
 }
 
 // Metode untuk SafeResults
@@ -191,31 +239,48 @@ func (spk *SafePathKeys) Add(key string) {
 }
 
 // Fungsi utama BFS multithreading
-func bfsMultiple(elementMap map[string]Element, target string, maxRecipes int) ([]TreeNode, int) {
-	target = strings.ToLower(target)
+func bfsMultiple(budget *SearchBudget, graph *RecipeGraph, target string, maxRecipes int, leaves LeafSet, order searchOrder) ([]TreeNode, int) {
+	target = canonicalName(target)
+	elementMap := graph.Elements
 
-	counter := &Counter{}
-
-	if isBasicElement(target) {
-		return []TreeNode{{Name: capitalize(target)}}, counter.Get()
+	if leaves.Has(target) {
+		return []TreeNode{{Name: graph.displayName(target)}}, budget.Nodes()
 	}
 
 	if elem, ok := elementMap[target]; !ok || len(elem.Recipes) == 0 {
-		return []TreeNode{}, counter.Get()
+		return []TreeNode{}, budget.Nodes()
+	}
+
+	var trees []TreeNode
+	if order.deterministic {
+		trees = bfsBuildRecipeTreesSequential(budget, target, elementMap, maxRecipes, leaves, order)
+	} else {
+		trees = bfsBuildRecipeTreesParallel(budget, target, elementMap, maxRecipes, leaves, order)
 	}
+	log.Printf("Total nodes visited: %d\n", budget.Nodes())
+	return trees, budget.Nodes()
+}
 
-	trees := bfsBuildRecipeTreesParallel(target, elementMap, maxRecipes, counter)
-	fmt.Printf("Total nodes visited: %d\n", counter.Get())
-	return trees, counter.Get()
+// bfsLimits mengisi batas kedalaman dan ukuran queue dengan default BFS jika tidak diberikan
+func bfsLimits(budget *SearchBudget) (maxDepth, maxQueue int) {
+	maxDepth, maxQueue = bfsMaxDepth, bfsMaxQueueSize
+	if budget.limits.MaxDepth > 0 {
+		maxDepth = budget.limits.MaxDepth
+	}
+	if budget.limits.MaxQueue > 0 {
+		maxQueue = budget.limits.MaxQueue
+	}
+	return maxDepth, maxQueue
 }
 
-func bfsBuildRecipeTreesParallel(target string, elementMap map[string]Element, maxRecipes int, counter *Counter) []TreeNode {
-	queue := newSafeQueue()
+func bfsBuildRecipeTreesParallel(budget *SearchBudget, target string, elementMap map[string]Element, maxRecipes int, leaves LeafSet, order searchOrder) []TreeNode {
+	maxDepth, maxQueue := bfsLimits(budget)
+	queue := newSafeQueue(maxQueue)
 	results := newSafeResults(maxRecipes)
 	pathKeys := newSafePathKeys()
 
 	// Inisialisasi queue dengan recipe awal
-	queue.Push(createInitialQueueItems(target, elementMap)...)
+	queue.Push(createInitialQueueItems(target, elementMap, leaves, order)...)
 
 	var wg sync.WaitGroup
 	done := make(chan struct{})
@@ -223,16 +288,23 @@ func bfsBuildRecipeTreesParallel(target string, elementMap map[string]Element, m
 	// Membuat worker goroutines
 	for i := 0; i < numWorkers; i++ {
 		wg.Add(1)
-		go worker(queue, results, pathKeys, elementMap, target, counter, &wg, done)
+		go worker(queue, results, pathKeys, elementMap, leaves, order, target, budget, maxDepth, &wg, done)
 	}
 
-	// Goroutine untuk memonitor kondisi selesai
+	// Goroutine untuk memonitor kondisi selesai atau pembatalan
 	go func() {
+		ticker := time.NewTicker(100 * time.Millisecond)
+		defer ticker.Stop()
 		for {
-			time.Sleep(100 * time.Millisecond)
-			if queue.Length() == 0 || results.IsFull() {
-				close(done) // Signal all workers to finish
-				break
+			select {
+			case <-budget.Done():
+				close(done)
+				return
+			case <-ticker.C:
+				if queue.Idle() || results.IsFull() || budget.Stopped() {
+					close(done) // Signal all workers to finish
+					return
+				}
 			}
 		}
 	}()
@@ -241,65 +313,122 @@ func bfsBuildRecipeTreesParallel(target string, elementMap map[string]Element, m
 	return results.GetTrees()
 }
 
+// bfsBuildRecipeTreesSequential adalah BFS untuk mode deterministic: satu goroutine memproses queue
+// secara FIFO, jadi hasil dan urutannya hanya bergantung pada input, dataset dan seed
+func bfsBuildRecipeTreesSequential(budget *SearchBudget, target string, elementMap map[string]Element, maxRecipes int, leaves LeafSet, order searchOrder) []TreeNode {
+	maxDepth, maxQueue := bfsLimits(budget)
+	queue := newSafeQueue(maxQueue)
+	results := newSafeResults(maxRecipes)
+	pathKeys := newSafePathKeys()
+	queue.Push(createInitialQueueItems(target, elementMap, leaves, order)...)
+
+	for queue.Length() > 0 && !results.IsFull() {
+		items := queue.Pop(1)
+		if len(items) == 0 || !budget.Visit() {
+			break
+		}
+		curr := items[0]
+
+		if curr.Depth > maxDepth {
+			budget.Truncate(truncatedByMaxDepth)
+			continue
+		}
+
+		if len(curr.Open) == 0 {
+			key := pathToStringKey(curr.Path)
+			if pathKeys.Check(key) || isStructuralDuplicate(curr.Path, elementMap, pathKeys) {
+				continue
+			}
+			pathKeys.Add(key)
+
+			fp := canonicalizeSteps(curr.Path, elementMap)
+			results.Add(buildTreeFromSteps(target, curr.Path, elementMap), fp)
+			continue
+		}
+
+		queue.Push(expandOpenElement(firstOpen(curr.Open), curr, elementMap, leaves, order)...)
+		if queue.PruneLargeWithPriority() {
+			budget.Truncate(truncatedByMaxQueue)
+		}
+		if queue.Exhausted() {
+			budget.Stop(truncatedByMaxQueue)
+		}
+	}
+	return results.GetTrees()
+}
+
 func worker(queue *SafeQueue, results *SafeResults, pathKeys *SafePathKeys,
-	elementMap map[string]Element, target string, counter *Counter,
+	elementMap map[string]Element, leaves LeafSet, order searchOrder, target string, budget *SearchBudget, maxDepth int,
 	wg *sync.WaitGroup, done chan struct{}) {
 	defer wg.Done()
 
-	for {
-		select {
-		case <-done:
-			return
-		default:
-			items := queue.Pop(batchSize)
-			if items == nil || len(items) == 0 {
-				runtime.Gosched()
-				continue
+	// process memperluas satu batch dan mengembalikan true jika worker harus berhenti
+	process := func(items []BuildQueueItem) bool {
+		defer queue.Release()
+		for _, curr := range items {
+			if isDone(done) || !budget.Visit() {
+				return true
 			}
 
-			for _, curr := range items {
-				counter.Increment()
+			if curr.Depth > maxDepth {
+				budget.Truncate(truncatedByMaxDepth)
+				continue
+			}
 
-				if curr.Depth > bfsMaxDepth {
+			if len(curr.Open) == 0 {
+				key := pathToStringKey(curr.Path)
+				if pathKeys.Check(key) || isStructuralDuplicate(curr.Path, elementMap, pathKeys) {
 					continue
 				}
+				pathKeys.Add(key)
 
-				if len(curr.Open) == 0 {
-					key := pathToStringKey(curr.Path)
-					if pathKeys.Check(key) || isStructuralDuplicate(curr.Path, elementMap, pathKeys) {
-						continue
-					}
-					pathKeys.Add(key)
-
-					fp := canonicalizeSteps(curr.Path, elementMap)
-					if !results.Add(TreeNode{}, fp) {
-						continue
-					}
-
-					tree := buildTreeFromSteps(target, curr.Path, elementMap)
-					results.mutex.Lock()
-					results.trees[len(results.trees)-1] = tree
-					results.mutex.Unlock()
-
-					if results.IsFull() {
-						return
-					}
-					continue
+				// Pohon dibangun sebelum Add supaya slot hasil tidak pernah kosong atau ditimpa worker lain
+				fp := canonicalizeSteps(curr.Path, elementMap)
+				if results.Add(buildTreeFromSteps(target, curr.Path, elementMap), fp) && results.IsFull() {
+					return true
 				}
+				continue
+			}
 
-				// Expand first open element
-				for openElem := range curr.Open {
-					queue.Push(expandOpenElement(openElem, curr, elementMap)...)
-					break
-				}
+			queue.Push(expandOpenElement(firstOpen(curr.Open), curr, elementMap, leaves, order)...)
+
+			if queue.PruneLargeWithPriority() {
+				budget.Truncate(truncatedByMaxQueue)
+			}
+			if queue.Exhausted() {
+				budget.Stop(truncatedByMaxQueue)
+			}
+		}
+		return false
+	}
 
-				queue.PruneLargeWithPriority()
+	for {
+		select {
+		case <-done:
+			return
+		default:
+			items := queue.Claim(batchSize)
+			if len(items) == 0 {
+				runtime.Gosched()
+				continue
+			}
+			if process(items) {
+				return
 			}
 		}
 	}
 }
 
-func isValidRecipe(a, b string, targetTier int, elementMap map[string]Element) bool {
+func isDone(done chan struct{}) bool {
+	select {
+	case <-done:
+		return true
+	default:
+		return false
+	}
+}
+
+func isValidRecipe(a, b string, targetTier int, elementMap map[string]Element, leaves LeafSet) bool {
 	elemA, okA := elementMap[a]
 	elemB, okB := elementMap[b]
 
@@ -310,29 +439,30 @@ func isValidRecipe(a, b string, targetTier int, elementMap map[string]Element) b
 	if elemA.Tier < 0 || elemB.Tier < 0 {
 		return true
 	}
-	return elemA.Tier < targetTier && elemB.Tier < targetTier
+	// Elemen yang sudah dimiliki tidak akan diekspansi, jadi tiernya tidak bisa membuat siklus
+	return (leaves[a] || elemA.Tier < targetTier) && (leaves[b] || elemB.Tier < targetTier)
 }
 
-func createInitialQueueItems(target string, elementMap map[string]Element) []BuildQueueItem {
+func createInitialQueueItems(target string, elementMap map[string]Element, leaves LeafSet, order searchOrder) []BuildQueueItem {
 	queue := []BuildQueueItem{}
 	targetTier := elementMap[target].Tier
 
-	for _, recipe := range elementMap[target].Recipes {
+	for _, recipe := range order.recipes(target, elementMap[target].Recipes) {
 		if len(recipe) != 2 {
 			continue
 		}
-		a := strings.ToLower(recipe[0])
-		b := strings.ToLower(recipe[1])
-		if !isValidRecipe(a, b, targetTier, elementMap) {
+		a := canonicalName(recipe[0])
+		b := canonicalName(recipe[1])
+		if !isValidRecipe(a, b, targetTier, elementMap, leaves) {
 			continue
 		}
 
 		step := RecipeStep{Element: target, Ingredients: []string{a, b}}
 		open := map[string]bool{}
-		if !isBasicElement(a) {
+		if !leaves[a] {
 			open[a] = true
 		}
-		if !isBasicElement(b) {
+		if !leaves[b] {
 			open[b] = true
 		}
 
@@ -345,27 +475,27 @@ func createInitialQueueItems(target string, elementMap map[string]Element) []Bui
 	return queue
 }
 
-func expandOpenElement(openElem string, curr BuildQueueItem, elementMap map[string]Element) []BuildQueueItem {
+func expandOpenElement(openElem string, curr BuildQueueItem, elementMap map[string]Element, leaves LeafSet, order searchOrder) []BuildQueueItem {
 	newItems := []BuildQueueItem{}
 	elemTier := elementMap[openElem].Tier
 
-	for _, recipe := range elementMap[openElem].Recipes {
+	for _, recipe := range order.recipes(openElem, elementMap[openElem].Recipes) {
 		if len(recipe) != 2 {
 			continue
 		}
-		a := strings.ToLower(recipe[0])
-		b := strings.ToLower(recipe[1])
-		if !isValidRecipe(a, b, elemTier, elementMap) {
+		a := canonicalName(recipe[0])
+		b := canonicalName(recipe[1])
+		if !isValidRecipe(a, b, elemTier, elementMap, leaves) {
 			continue
 		}
 
 		newStep := RecipeStep{Element: openElem, Ingredients: []string{a, b}}
 		newOpen := copyOpenMap(curr.Open)
 		delete(newOpen, openElem)
-		if !isBasicElement(a) {
+		if !leaves[a] {
 			newOpen[a] = true
 		}
-		if !isBasicElement(b) {
+		if !leaves[b] {
 			newOpen[b] = true
 		}
 
@@ -382,6 +512,17 @@ func expandOpenElement(openElem string, curr BuildQueueItem, elementMap map[stri
 	return newItems
 }
 
+// firstOpen memilih elemen terbuka dengan nama terkecil supaya ekspansi tidak bergantung pada urutan map
+func firstOpen(open map[string]bool) string {
+	first := ""
+	for name := range open {
+		if first == "" || name < first {
+			first = name
+		}
+	}
+	return first
+}
+
 func copyOpenMap(orig map[string]bool) map[string]bool {
 	newMap := make(map[string]bool)
 	for k, v := range orig {
@@ -418,9 +559,9 @@ func sortTreeChildren(node *TreeNode) {
 func isStructuralDuplicate(steps []RecipeStep, elementMap map[string]Element, pathKeys *SafePathKeys) bool {
 	var normalized []string
 	for _, step := range steps {
-		a := strings.ToLower(step.Ingredients[0])
-		b := strings.ToLower(step.Ingredients[1])
-		e := strings.ToLower(step.Element)
+		a := canonicalName(step.Ingredients[0])
+		b := canonicalName(step.Ingredients[1])
+		e := canonicalName(step.Element)
 
 		aTier := elementMap[a].Tier
 		bTier := elementMap[b].Tier
@@ -448,8 +589,8 @@ func isStructuralDuplicate(steps []RecipeStep, elementMap map[string]Element, pa
 func pathToStringKey(steps []RecipeStep) string {
 	keys := make([]string, 0, len(steps))
 	for _, step := range steps {
-		a := strings.ToLower(step.Ingredients[0])
-		b := strings.ToLower(step.Ingredients[1])
+		a := canonicalName(step.Ingredients[0])
+		b := canonicalName(step.Ingredients[1])
 		if a > b {
 			a, b = b, a
 		}
@@ -472,22 +613,23 @@ func canonicalizeTree(node TreeNode) string {
 }
 
 // Fungsi live update untuk WebSocket
-func bfsMultipleLive(elementMap map[string]Element, target string, maxRecipes int, delay int, conn *websocket.Conn) []TreeNode {
-	target = strings.ToLower(target)
-	counter := &Counter{}
+func bfsMultipleLive(budget *SearchBudget, graph *RecipeGraph, target string, maxRecipes int, leaves LeafSet, order searchOrder, delay int, conn *websocket.Conn) ([]TreeNode, int) {
+	target = canonicalName(target)
+	elementMap := graph.Elements
+	maxDepth, maxQueue := bfsLimits(budget)
 	pathKeys := newSafePathKeys()
 	results := newSafeResults(maxRecipes)
 
-	if isBasicElement(target) {
-		return []TreeNode{{Name: capitalize(target)}}
+	if leaves.Has(target) {
+		return []TreeNode{{Name: graph.displayName(target)}}, budget.Nodes()
 	}
 
 	if elem, ok := elementMap[target]; !ok || len(elem.Recipes) == 0 {
-		return []TreeNode{}
+		return []TreeNode{}, budget.Nodes()
 	}
 
-	queue := newSafeQueue()
-	queue.Push(createInitialQueueItems(target, elementMap)...)
+	queue := newSafeQueue(maxQueue)
+	queue.Push(createInitialQueueItems(target, elementMap, leaves, order)...)
 
 	conn.WriteJSON(map[string]interface{}{
 		"status":       "Starting",
@@ -500,11 +642,10 @@ func bfsMultipleLive(elementMap map[string]Element, target string, maxRecipes in
 
 	for queue.Length() > 0 && !results.IsFull() {
 		items := queue.Pop(1)
-		if len(items) == 0 {
+		if len(items) == 0 || !budget.Visit() {
 			break
 		}
 		curr := items[0]
-		counter.Increment()
 
 		if len(curr.Path) > 0 {
 			lastStep := curr.Path[len(curr.Path)-1]
@@ -513,16 +654,17 @@ func bfsMultipleLive(elementMap map[string]Element, target string, maxRecipes in
 				tree := buildTreeFromSteps(lastStep.Element, []RecipeStep{lastStep}, elementMap)
 				conn.WriteJSON(map[string]interface{}{
 					"status":       "Preview",
-					"message":      "Discovered: " + capitalize(lastStep.Element),
+					"message":      "Discovered: " + graph.displayName(lastStep.Element),
 					"treeData":     []TreeNode{tree},
-					"nodesVisited": counter.Get(),
+					"nodesVisited": budget.Nodes(),
 				})
-				time.Sleep(time.Duration(delay) * time.Millisecond)
+				sleepCtx(budget.Context(), time.Duration(delay)*time.Millisecond)
 				previewSent[previewKey] = true
 			}
 		}
 
-		if curr.Depth > bfsMaxDepth {
+		if curr.Depth > maxDepth {
+			budget.Truncate(truncatedByMaxDepth)
 			continue
 		}
 
@@ -534,46 +676,49 @@ func bfsMultipleLive(elementMap map[string]Element, target string, maxRecipes in
 			pathKeys.Add(key)
 
 			fp := canonicalizeSteps(curr.Path, elementMap)
-			if !results.Add(TreeNode{}, fp) {
+			tree := buildTreeFromSteps(target, curr.Path, elementMap)
+			if !results.Add(tree, fp) {
 				continue
 			}
 
-			tree := buildTreeFromSteps(target, curr.Path, elementMap)
-			results.trees[len(results.trees)-1] = tree
-
 			conn.WriteJSON(map[string]interface{}{
 				"status":       "Final",
 				"message":      "Final tree found!",
 				"treeData":     []TreeNode{tree},
-				"nodesVisited": counter.Get(),
+				"nodesVisited": budget.Nodes(),
 			})
-			time.Sleep(time.Duration(delay) * time.Millisecond)
+			sleepCtx(budget.Context(), time.Duration(delay)*time.Millisecond)
 			continue
 		}
 
-		for openElem := range curr.Open {
-			queue.Push(expandOpenElement(openElem, curr, elementMap)...)
-			break
-		}
+		queue.Push(expandOpenElement(firstOpen(curr.Open), curr, elementMap, leaves, order)...)
 
-		queue.PruneLargeWithPriority()
+		if queue.PruneLargeWithPriority() {
+			budget.Truncate(truncatedByMaxQueue)
+		}
+		if queue.Exhausted() {
+			budget.Stop(truncatedByMaxQueue)
+		}
 	}
 
-	conn.WriteJSON(map[string]interface{}{
-		"status":       "Completed",
-		"message":      fmt.Sprintf("Found %d recipes, explored %d nodes", results.Count(), counter.Get()),
-		"nodesVisited": counter.Get(),
-	})
+	// Status akhir (Completed atau Cancelled) hanya dikirim oleh handleWebSocket bersama treeData
+	if budget.Context().Err() == nil {
+		conn.WriteJSON(map[string]interface{}{
+			"status":       "Progress",
+			"message":      fmt.Sprintf("Found %d recipes, explored %d nodes", results.Count(), budget.Nodes()),
+			"nodesVisited": budget.Nodes(),
+		})
+	}
 
-	return results.GetTrees()
+	return results.GetTrees(), budget.Nodes()
 }
 
 func canonicalizeSteps(steps []RecipeStep, elementMap map[string]Element) string {
 	var normalized []string
 	for _, step := range steps {
-		a := strings.ToLower(step.Ingredients[0])
-		b := strings.ToLower(step.Ingredients[1])
-		e := strings.ToLower(step.Element)
+		a := canonicalName(step.Ingredients[0])
+		b := canonicalName(step.Ingredients[1])
+		e := canonicalName(step.Element)
 		if a > b {
 			a, b = b, a
 		}
@@ -582,3 +727,24 @@ func canonicalizeSteps(steps []RecipeStep, elementMap map[string]Element) string
 	sort.Strings(normalized)
 	return strings.Join(normalized, "|")
 }
+
+type bfsSolver struct{}
+
+func (bfsSolver) Name() string  { return "BFS" }
+func (bfsSolver) Label() string { return "BFS" }
+
+func (bfsSolver) Solve(ctx context.Context, graph *RecipeGraph, target string, opts SolverOptions) SearchResult {
+	budget := newSearchBudget(ctx, opts.Limits)
+	trees, _ := bfsMultiple(budget, graph, target, opts.MaxRecipes, graph.newLeafSet(opts.Inventory), opts.order())
+	return budget.result(trees)
+}
+
+func (bfsSolver) SolveLive(ctx context.Context, graph *RecipeGraph, target string, opts SolverOptions, conn *websocket.Conn) SearchResult {
+	budget := newSearchBudget(ctx, opts.Limits)
+	trees, _ := bfsMultipleLive(budget, graph, target, opts.MaxRecipes, graph.newLeafSet(opts.Inventory), opts.order(), opts.Delay, conn)
+	return budget.result(trees)
+}
+
+func init() {
+	registerSolver(bfsSolver{})
+}
@@ -1,35 +1,63 @@
 package main
 
 import (
-	"strings"
+	"context"
+	"sort"
 	"sync"
 	"sync/atomic"
+
+	"github.com/gorilla/websocket"
 )
 
 type BIDTreeData struct {
-	target       	  string
-	maxRecipes   	  int
+	budget            *SearchBudget
+	target            string
+	maxRecipes        int
 	maxRecipesPerElmt int
+	leaves            LeafSet
 
-	forwardQueue  [][]string        
+	forwardQueue  [][]string
 	forwardTrees  map[string][]TreeNode
-	forwardDepths map[string]int     
+	forwardDepths map[string]int
+	// forwardSeen adalah jumlah pohon tiap elemen yang sudah pernah dikombinasikan,
+	// jadi setiap layer hanya menggabungkan pohon yang baru muncul di layer sebelumnya
+	forwardSeen map[string]int
 
-	backwardQueue     [][]string       
-	backwardReached map[string]bool  
-	backwardDepths  map[string]int     
+	backwardQueue   [][]string
+	backwardReached map[string]bool
+	backwardDepths  map[string]int
 
 	results        []TreeNode
 	processedTrees map[string]bool
-	resultMutex    sync.Mutex     
+	resultMutex    sync.Mutex
+
+	maxDepth int
+	gotoEnd  bool
+	// capped bernilai true jika maxRecipesPerElmt membuang kombinasi yang seharusnya dibuat;
+	// capReason adalah alasan truncatedBy yang dilaporkan untuk itu
+	capped    bool
+	capReason string
+
+	// names adalah elemen yang relevan untuk target dalam urutan ekspansi maju, terurut atau diacak dengan seed
+	names []string
+	order searchOrder
+}
 
-	maxDepth 	 int
-	gotoEnd  	 bool
-	nodesVisited int64
+// Default batas layer dan jumlah pohon per elemen jika client tidak memberi maxDepth/maxQueue
+const (
+	bidMaxDepth          = 20
+	bidMaxRecipesPerElmt = 20000
+)
+
+// stopped bernilai true jika hasil sudah cukup atau pencarian dibatalkan
+func (b *BIDTreeData) stopped() bool {
+	return b.gotoEnd || b.budget.Stopped()
 }
 
 func (b *BIDTreeData) addResult(tree TreeNode) {
-	if b.gotoEnd { return }
+	if b.gotoEnd {
+		return
+	}
 
 	b.resultMutex.Lock()
 	defer b.resultMutex.Unlock()
@@ -52,13 +80,13 @@ func (b *BIDTreeData) addResult(tree TreeNode) {
 func initializeForwardSearch(b *BIDTreeData, elementMap map[string]Element) {
 	var initialForward []string
 	initialMap := make(map[string]bool)
-	for elName := range elementMap {
-		elNameLower := strings.ToLower(elName)
-		if isBasicElement(elNameLower) {
+	for _, elName := range b.names {
+		elNameLower := canonicalName(elName)
+		if b.leaves.Has(elNameLower) {
 			if _, ok := elementMap[elNameLower]; ok {
-				tree := TreeNode{Name: capitalize(elNameLower)}
+				tree := TreeNode{Name: displayName(elementMap, elNameLower)}
 				if len(b.forwardTrees[elNameLower]) < b.maxRecipesPerElmt {
-					atomic.AddInt64(&b.nodesVisited, 1)
+					b.budget.Visit()
 					b.forwardTrees[elNameLower] = append(b.forwardTrees[elNameLower], tree)
 					b.forwardDepths[elNameLower] = 0
 					if !initialMap[elNameLower] {
@@ -76,42 +104,60 @@ func initializeBackwardSearch(b *BIDTreeData) {
 	b.backwardQueue[0] = []string{b.target}
 	b.backwardReached[b.target] = true
 	b.backwardDepths[b.target] = 0
-	atomic.AddInt64(&b.nodesVisited, 1)
+	b.budget.Visit()
 }
 
 func expandForwardLayer(b *BIDTreeData, fLayer int, elementMap map[string]Element) bool {
-	if b.gotoEnd || fLayer >= len(b.forwardQueue) || len(b.forwardQueue[fLayer]) == 0 {
+	if b.stopped() || fLayer >= len(b.forwardQueue) || len(b.forwardQueue[fLayer]) == 0 {
 		return false
 	}
 
 	nextForwardLayerElements := make(map[string]bool)
 
-	for potentialProductLower, productElem := range elementMap {
-		if b.gotoEnd { break }
+	// Pohon yang ditambahkan selama layer ini baru dipakai sebagai bahan di layer berikutnya
+	current := make(map[string]int, len(b.forwardTrees))
+	for name, trees := range b.forwardTrees {
+		current[name] = len(trees)
+	}
+	defer func() { b.forwardSeen = current }()
 
-		if len(b.forwardTrees[potentialProductLower]) >= b.maxRecipesPerElmt {
-			continue
+	for _, potentialProductLower := range b.names {
+		if b.stopped() {
+			break
 		}
-		if depth, processed := b.forwardDepths[potentialProductLower]; processed && depth < fLayer+1 && len(b.forwardTrees[potentialProductLower]) >= b.maxRecipesPerElmt {
+		productElem := elementMap[potentialProductLower]
+
+		// Elemen yang sudah dimiliki cukup jadi daun, tidak perlu pohon lain
+		if b.leaves[potentialProductLower] {
 			continue
 		}
 
 		productTier := productElem.Tier
-		for _, recipe := range productElem.Recipes {
-			if b.gotoEnd { break }
-			if len(recipe) != 2 { continue }
-			p1 := strings.ToLower(recipe[0])
-			p2 := strings.ToLower(recipe[1])
+		for _, recipe := range b.order.recipes(potentialProductLower, productElem.Recipes) {
+			if b.stopped() {
+				break
+			}
+			if len(recipe) != 2 {
+				continue
+			}
+			p1 := canonicalName(recipe[0])
+			p2 := canonicalName(recipe[1])
 
 			trees1, ok1 := b.forwardTrees[p1]
 			trees2, ok2 := b.forwardTrees[p2]
+			trees1, trees2 = trees1[:current[p1]], trees2[:current[p2]]
+			seen1, seen2 := b.forwardSeen[p1], b.forwardSeen[p2]
+			if seen1 == len(trees1) && seen2 == len(trees2) {
+				// Semua kombinasi resep ini sudah dibuat di layer sebelumnya
+				continue
+			}
 			depth1, depthOk1 := b.forwardDepths[p1]
 			depth2, depthOk2 := b.forwardDepths[p2]
 
 			if ok1 && ok2 && depthOk1 && depthOk2 && (depth1 <= fLayer && depth2 <= fLayer) {
 				eP1, p1Exists := elementMap[p1]
 				eP2, p2Exists := elementMap[p2]
-				if !p1Exists || !p2Exists || eP1.Tier >= productTier || eP2.Tier >= productTier {
+				if !p1Exists || !p2Exists || (eP1.Tier >= productTier && !b.leaves[p1]) || (eP2.Tier >= productTier && !b.leaves[p2]) {
 					continue
 				}
 
@@ -119,62 +165,38 @@ func expandForwardLayer(b *BIDTreeData, fLayer int, elementMap map[string]Elemen
 				availableSlots := b.maxRecipesPerElmt - len(existingTrees)
 
 				if availableSlots <= 0 {
+					b.capped = true
 					continue
 				}
 
-				var wg sync.WaitGroup
-				combinedChan := make(chan TreeNode, availableSlots)
-				nodesToCombine := len(trees1) * len(trees2)
-				maxCombinations := availableSlots
-				if nodesToCombine < maxCombinations {
-					maxCombinations = nodesToCombine
-				}
-				var combinationCount int32
-
-				wg.Add(len(trees1))
-				for i := range trees1 {
-					go func(t1 TreeNode) {
-						defer wg.Done()
-						for _, t2 := range trees2 {
-							currentCount := atomic.AddInt32(&combinationCount, 1)
-							if currentCount > int32(maxCombinations) {
-								atomic.AddInt32(&combinationCount, -1)
-								return
-							}
-							if b.gotoEnd { return }
-
-							newNode := TreeNode{
-								Name:     capitalize(potentialProductLower),
-								Children: []TreeNode{t1, t2},
-							}
-							combinedChan <- newNode
-						}
-					}(trees1[i])
-				}
+				productName := displayName(elementMap, potentialProductLower)
 
+				// Pasangan baru: (pohon baru p1 x semua pohon p2) lalu (pohon lama p1 x pohon baru p2)
 				var combinedTrees []TreeNode
-				collectorWg := sync.WaitGroup{}
-				collectorWg.Add(1)
-				go func() {
-					defer collectorWg.Done()
-					for i := 0; i < maxCombinations; i++ {
-						tree, ok := <-combinedChan
-						if !ok {
-							break
-						}
-						combinedTrees = append(combinedTrees, tree)
+				pairs := [][2][]TreeNode{
+					{trees1[seen1:], trees2},
+					{trees1[:seen1], trees2[seen2:]},
+				}
+				for _, pair := range pairs {
+					maxCombinations := min(availableSlots-len(combinedTrees), len(pair[0])*len(pair[1]))
+					if maxCombinations < len(pair[0])*len(pair[1]) {
+						b.capped = true
 					}
-				}()
-
-				wg.Wait()
-				close(combinedChan)
-				collectorWg.Wait()
+					if maxCombinations <= 0 {
+						continue
+					}
+					if b.order.deterministic {
+						combinedTrees = append(combinedTrees, combineTreesOrdered(b, productName, pair[0], pair[1], maxCombinations)...)
+					} else {
+						combinedTrees = append(combinedTrees, combineTreesParallel(b, productName, pair[0], pair[1], maxCombinations)...)
+					}
+				}
 
 				if len(combinedTrees) > 0 {
 					b.forwardTrees[potentialProductLower] = append(existingTrees, combinedTrees...)
 
 					if _, depthExists := b.forwardDepths[potentialProductLower]; !depthExists || b.forwardDepths[potentialProductLower] > fLayer+1 {
-						atomic.AddInt64(&b.nodesVisited, 1)
+						b.budget.Visit()
 						b.forwardDepths[potentialProductLower] = fLayer + 1
 					}
 					if len(b.forwardTrees[potentialProductLower]) > 0 {
@@ -199,6 +221,7 @@ func expandForwardLayer(b *BIDTreeData, fLayer int, elementMap map[string]Elemen
 			nextQueue = append(nextQueue, elem)
 		}
 	}
+	sort.Strings(nextQueue)
 	if len(nextQueue) > 0 {
 		b.forwardQueue = append(b.forwardQueue, nextQueue)
 		return true
@@ -206,8 +229,75 @@ func expandForwardLayer(b *BIDTreeData, fLayer int, elementMap map[string]Elemen
 	return false
 }
 
+// combineTreesParallel menggabungkan pohon bahan dengan satu goroutine per pohon di trees1.
+// Urutan hasilnya bergantung pada penjadwalan goroutine.
+func combineTreesParallel(b *BIDTreeData, productName string, trees1, trees2 []TreeNode, maxCombinations int) []TreeNode {
+	var wg sync.WaitGroup
+	combinedChan := make(chan TreeNode, maxCombinations)
+	var combinationCount int32
+
+	wg.Add(len(trees1))
+	for i := range trees1 {
+		go func(t1 TreeNode) {
+			defer wg.Done()
+			for _, t2 := range trees2 {
+				currentCount := atomic.AddInt32(&combinationCount, 1)
+				if currentCount > int32(maxCombinations) {
+					atomic.AddInt32(&combinationCount, -1)
+					return
+				}
+				if b.stopped() {
+					return
+				}
+
+				newNode := TreeNode{
+					Name:     productName,
+					Children: []TreeNode{t1, t2},
+				}
+				combinedChan <- newNode
+			}
+		}(trees1[i])
+	}
+
+	var combinedTrees []TreeNode
+	collectorWg := sync.WaitGroup{}
+	collectorWg.Add(1)
+	go func() {
+		defer collectorWg.Done()
+		for i := 0; i < maxCombinations; i++ {
+			tree, ok := <-combinedChan
+			if !ok {
+				break
+			}
+			combinedTrees = append(combinedTrees, tree)
+		}
+	}()
+
+	wg.Wait()
+	close(combinedChan)
+	collectorWg.Wait()
+	return combinedTrees
+}
+
+// combineTreesOrdered adalah versi mode deterministic: trees1 x trees2 digabung berurutan
+func combineTreesOrdered(b *BIDTreeData, productName string, trees1, trees2 []TreeNode, maxCombinations int) []TreeNode {
+	var combinedTrees []TreeNode
+	for _, t1 := range trees1 {
+		for _, t2 := range trees2 {
+			if len(combinedTrees) >= maxCombinations || b.stopped() {
+				return combinedTrees
+			}
+			combinedTrees = append(combinedTrees, TreeNode{
+				Name:     productName,
+				Children: []TreeNode{t1, t2},
+			})
+		}
+	}
+	return combinedTrees
+}
+
 func expandBackwardLayer(b *BIDTreeData, bLayer int, elementMap map[string]Element) bool {
-	if b.gotoEnd || bLayer >= len(b.backwardQueue) || len(b.backwardQueue[bLayer]) == 0 {
+	if b.stopped() || bLayer >= len(b.backwardQueue) || len(b.backwardQueue[bLayer]) == 0 {
 		return false
 	}
 
@@ -215,25 +305,33 @@ func expandBackwardLayer(b *BIDTreeData, bLayer int, elementMap map[string]Eleme
 	currentLayerElements := b.backwardQueue[bLayer]
 
 	for _, elemToExpand := range currentLayerElements {
-		if b.gotoEnd { break }
+		if b.stopped() {
+			break
+		}
 		elemDetails, ok := elementMap[elemToExpand]
-		if !ok { continue }
+		if !ok {
+			continue
+		}
 
-		for _, recipe := range elemDetails.Recipes {
-			if b.gotoEnd { break }
-			if len(recipe) != 2 { continue }
-			p1 := strings.ToLower(recipe[0])
-			p2 := strings.ToLower(recipe[1])
+		for _, recipe := range b.order.recipes(elemToExpand, elemDetails.Recipes) {
+			if b.stopped() {
+				break
+			}
+			if len(recipe) != 2 {
+				continue
+			}
+			p1 := canonicalName(recipe[0])
+			p2 := canonicalName(recipe[1])
 
 			eP1, ok1 := elementMap[p1]
 			eP2, ok2 := elementMap[p2]
-			if !ok1 || !ok2 || eP1.Tier >= elemDetails.Tier || eP2.Tier >= elemDetails.Tier {
+			if !ok1 || !ok2 || (eP1.Tier >= elemDetails.Tier && !b.leaves[p1]) || (eP2.Tier >= elemDetails.Tier && !b.leaves[p2]) {
 				continue
 			}
 
 			processBackwardIngredient := func(ing string) {
 				if !b.backwardReached[ing] {
-					atomic.AddInt64(&b.nodesVisited, 1)
+					b.budget.Visit()
 					b.backwardReached[ing] = true
 					b.backwardDepths[ing] = bLayer + 1
 					nextBackwardLayerElements[ing] = true
@@ -256,6 +354,7 @@ func expandBackwardLayer(b *BIDTreeData, bLayer int, elementMap map[string]Eleme
 	for elem := range nextBackwardLayerElements {
 		nextQueue = append(nextQueue, elem)
 	}
+	sort.Strings(nextQueue)
 	if len(nextQueue) > 0 {
 		b.backwardQueue = append(b.backwardQueue, nextQueue)
 		return true
@@ -263,11 +362,42 @@ func expandBackwardLayer(b *BIDTreeData, bLayer int, elementMap map[string]Eleme
 	return false
 }
 
-func bidirectionalMultiple(target string, maxRecipes int, maxRecipesPerElmt int) ([]TreeNode, int) {
-	targetLower := strings.ToLower(target)
+// bidRelevantNames mengembalikan elemen yang bisa menjadi bagian pohon target (target dan semua bahannya,
+// secara transitif) dalam urutan eksplorasi. Ekspansi maju hanya perlu membangun pohon untuk elemen ini.
+func bidRelevantNames(graph *RecipeGraph, target string, leaves LeafSet, order searchOrder) []string {
+	relevant := map[string]bool{target: true}
+	stack := []string{target}
+	for len(stack) > 0 {
+		name := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if leaves[name] {
+			continue
+		}
+		for _, recipe := range validRecipes(graph, name, leaves) {
+			for _, ing := range recipe {
+				if !relevant[ing] {
+					relevant[ing] = true
+					stack = append(stack, ing)
+				}
+			}
+		}
+	}
+
+	var names []string
+	for _, name := range order.names(graph.Names) {
+		if relevant[name] {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func bidirectionalMultiple(budget *SearchBudget, graph *RecipeGraph, target string, maxRecipes int, maxRecipesPerElmt int, capReason string, leaves LeafSet, order searchOrder) ([]TreeNode, int) {
+	targetLower := canonicalName(target)
+	elementMap := graph.Elements
 
-	if isBasicElement(targetLower) {
-		return []TreeNode{{Name: capitalize(targetLower)}}, 1
+	if leaves.Has(targetLower) {
+		return []TreeNode{{Name: displayName(elementMap, targetLower)}}, 1
 	}
 	targetElem, exists := elementMap[targetLower]
 	if !exists || len(targetElem.Recipes) == 0 {
@@ -275,27 +405,38 @@ func bidirectionalMultiple(target string, maxRecipes int, maxRecipesPerElmt int)
 	}
 
 	BIDData := &BIDTreeData{
+		budget:            budget,
 		target:            targetLower,
 		maxRecipes:        maxRecipes,
 		maxRecipesPerElmt: maxRecipesPerElmt,
+		capReason:         capReason,
+		leaves:            leaves,
 		forwardQueue:      make([][]string, 1),
 		forwardTrees:      make(map[string][]TreeNode),
 		forwardDepths:     make(map[string]int),
+		forwardSeen:       make(map[string]int),
 		backwardQueue:     make([][]string, 1),
 		backwardReached:   make(map[string]bool),
 		backwardDepths:    make(map[string]int),
 		results:           make([]TreeNode, 0),
 		processedTrees:    make(map[string]bool),
-		maxDepth:          20,
+		maxDepth:          bidMaxDepth,
 		gotoEnd:           false,
+		names:             bidRelevantNames(graph, targetLower, leaves, order),
+		order:             order,
+	}
+
+	if budget.limits.MaxDepth > 0 {
+		BIDData.maxDepth = budget.limits.MaxDepth
 	}
 
 	initializeForwardSearch(BIDData, elementMap)
 	initializeBackwardSearch(BIDData)
 
 	fLayer, bLayer := 0, 0
+	exhausted := false
 	for fLayer < BIDData.maxDepth && bLayer < BIDData.maxDepth {
-		if BIDData.gotoEnd {
+		if BIDData.stopped() {
 			break
 		}
 
@@ -304,7 +445,7 @@ func bidirectionalMultiple(target string, maxRecipes int, maxRecipesPerElmt int)
 			fLayer++
 		}
 
-		if BIDData.gotoEnd {
+		if BIDData.stopped() {
 			break
 		}
 
@@ -313,12 +454,55 @@ func bidirectionalMultiple(target string, maxRecipes int, maxRecipesPerElmt int)
 			bLayer++
 		}
 
-		if !forwardExpanded && !backwardExpanded &&
-		   (fLayer >= len(BIDData.forwardQueue) || len(BIDData.forwardQueue[fLayer]) == 0) &&
-		   (bLayer >= len(BIDData.backwardQueue) || len(BIDData.backwardQueue[bLayer]) == 0) {
+		// Layer yang gagal diperluas akan memberi hasil yang sama jika diulang, jadi pencarian sudah habis
+		if !forwardExpanded && !backwardExpanded {
+			exhausted = true
 			break
 		}
 	}
 
-	return BIDData.results, int(BIDData.nodesVisited)
-}
\ No newline at end of file
+	// maxDepth hanya dilaporkan jika masih ada layer yang belum diperluas saat batasnya tercapai
+	if !exhausted && !BIDData.stopped() && (fLayer >= BIDData.maxDepth || bLayer >= BIDData.maxDepth) {
+		budget.Truncate(truncatedByMaxDepth)
+	}
+	if !BIDData.stopped() && BIDData.capped {
+		budget.Truncate(BIDData.capReason)
+	}
+
+	return BIDData.results, budget.Nodes()
+}
+
+// bidTreeLimit mengembalikan batas pohon per elemen dan alasan truncatedBy jika batas itu tercapai.
+// Batasnya maxQueue client (atau bawaan), paling banyak maxRecipes*1000; maxQueue hanya dilaporkan
+// jika memang batas client itu yang memotong.
+func bidTreeLimit(opts SolverOptions) (int, string) {
+	perElmt, reason := bidMaxRecipesPerElmt, truncatedByTreeLimit
+	if opts.Limits.MaxQueue > 0 {
+		perElmt = opts.Limits.MaxQueue
+		if perElmt <= opts.MaxRecipes*1000 {
+			reason = truncatedByMaxQueue
+		}
+	}
+	return min(opts.MaxRecipes*1000, perElmt), reason
+}
+
+type bidirectionalSolver struct{}
+
+func (bidirectionalSolver) Name() string  { return "BID" }
+func (bidirectionalSolver) Label() string { return "Bidirectional Search" }
+
+func (bidirectionalSolver) Solve(ctx context.Context, graph *RecipeGraph, target string, opts SolverOptions) SearchResult {
+	budget := newSearchBudget(ctx, opts.Limits)
+	perElmt, capReason := bidTreeLimit(opts)
+	trees, _ := bidirectionalMultiple(budget, graph, target, opts.MaxRecipes, perElmt, capReason, graph.newLeafSet(opts.Inventory), opts.order())
+	return budget.result(trees)
+}
+
+// Bidirectional belum punya mode live, jadi hasil akhirnya langsung dikirim
+func (s bidirectionalSolver) SolveLive(ctx context.Context, graph *RecipeGraph, target string, opts SolverOptions, conn *websocket.Conn) SearchResult {
+	return s.Solve(ctx, graph, target, opts)
+}
+
+func init() {
+	registerSolver(bidirectionalSolver{})
+}
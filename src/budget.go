@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Alasan pencarian berhenti sebelum selesai, dikirim sebagai "truncatedBy"
+const (
+	truncatedByTimeout   = "timeout"
+	truncatedByCancelled = "cancelled"
+	truncatedByMaxNodes  = "maxNodes"
+	truncatedByMaxDepth  = "maxDepth"
+	truncatedByMaxQueue  = "maxQueue"
+	// truncatedByTreeLimit: BID membuang pohon karena batas pohon per elemen dari maxRecipes*1000 atau
+	// bawaan server (20000), bukan karena maxQueue client; maxRecipes atau maxQueue yang lebih besar menaikkannya
+	truncatedByTreeLimit = "treeLimit"
+)
+
+// SearchLimits adalah batas sumber daya satu pencarian.
+// Nilai 0 berarti memakai default masing-masing solver.
+type SearchLimits struct {
+	Timeout  time.Duration
+	MaxNodes int
+	MaxDepth int
+	MaxQueue int
+}
+
+// serverLimits adalah batas atas yang tidak bisa dilewati oleh permintaan client
+var serverLimits = SearchLimits{
+	Timeout:  2 * time.Minute,
+	MaxNodes: 5000000,
+	MaxDepth: 500,
+	MaxQueue: 500000,
+}
+
+// clampLimits membatasi permintaan client dengan serverLimits
+func clampLimits(req SearchLimits) SearchLimits {
+	clamp := func(v, limit int) int {
+		if v <= 0 {
+			return 0
+		}
+		return min(v, limit)
+	}
+
+	limits := SearchLimits{
+		Timeout:  serverLimits.Timeout,
+		MaxNodes: clamp(req.MaxNodes, serverLimits.MaxNodes),
+		MaxDepth: clamp(req.MaxDepth, serverLimits.MaxDepth),
+		MaxQueue: clamp(req.MaxQueue, serverLimits.MaxQueue),
+	}
+	if req.Timeout > 0 && req.Timeout < limits.Timeout {
+		limits.Timeout = req.Timeout
+	}
+	return limits
+}
+
+// SearchResult adalah hasil sebuah solver, termasuk alasan berhenti jika terpotong
+type SearchResult struct {
+	Trees        []TreeNode
+	NodesVisited int
+	TruncatedBy  string
+}
+
+// SearchBudget menghitung node yang dikunjungi dan mencatat batas pertama yang tercapai.
+// Aman dipakai dari banyak goroutine.
+type SearchBudget struct {
+	ctx    context.Context
+	limits SearchLimits
+	nodes  atomic.Int64
+
+	reasonMutex sync.Mutex
+	reason      string
+	exhausted   atomic.Bool
+}
+
+func newSearchBudget(ctx context.Context, limits SearchLimits) *SearchBudget {
+	return &SearchBudget{ctx: ctx, limits: limits}
+}
+
+// Visit menghitung satu node; mengembalikan false jika pencarian harus berhenti
+func (b *SearchBudget) Visit() bool {
+	n := b.nodes.Add(1)
+	if b.limits.MaxNodes > 0 && n > int64(b.limits.MaxNodes) {
+		b.Stop(truncatedByMaxNodes)
+	}
+	return !b.Stopped()
+}
+
+func (b *SearchBudget) Nodes() int {
+	return int(b.nodes.Load())
+}
+
+// Stopped bernilai true jika ctx selesai atau jumlah node sudah habis
+func (b *SearchBudget) Stopped() bool {
+	return b.exhausted.Load() || b.ctx.Err() != nil
+}
+
+// Done mengikuti ctx pencarian, untuk dipakai di select
+func (b *SearchBudget) Done() <-chan struct{} {
+	return b.ctx.Done()
+}
+
+func (b *SearchBudget) Context() context.Context {
+	return b.ctx
+}
+
+// Truncate mencatat bahwa sebagian ruang pencarian dilewati. Hanya alasan pertama yang disimpan.
+func (b *SearchBudget) Truncate(reason string) {
+	b.reasonMutex.Lock()
+	defer b.reasonMutex.Unlock()
+	if b.reason == "" {
+		b.reason = reason
+	}
+}
+
+// Stop mencatat reason lalu menghentikan pencarian seperti maxNodes yang habis,
+// untuk batas yang membuat sisa pencarian tidak ada gunanya dilanjutkan
+func (b *SearchBudget) Stop(reason string) {
+	b.Truncate(reason)
+	b.exhausted.Store(true)
+}
+
+// TruncatedBy mengembalikan alasan pencarian terpotong, atau "" jika selesai normal
+func (b *SearchBudget) TruncatedBy() string {
+	if err := b.ctx.Err(); err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return truncatedByTimeout
+		}
+		return truncatedByCancelled
+	}
+	b.reasonMutex.Lock()
+	defer b.reasonMutex.Unlock()
+	return b.reason
+}
+
+func (b *SearchBudget) result(trees []TreeNode) SearchResult {
+	return SearchResult{
+		Trees:        trees,
+		NodesVisited: b.Nodes(),
+		TruncatedBy:  b.TruncatedBy(),
+	}
+}
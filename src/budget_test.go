@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestClampLimits(t *testing.T) {
+	tests := []struct {
+		name string
+		req  SearchLimits
+		want SearchLimits
+	}{
+		{"defaults", SearchLimits{}, SearchLimits{Timeout: serverLimits.Timeout}},
+		{"within caps", SearchLimits{Timeout: time.Second, MaxNodes: 10, MaxDepth: 5, MaxQueue: 100}, SearchLimits{Timeout: time.Second, MaxNodes: 10, MaxDepth: 5, MaxQueue: 100}},
+		{"above caps", SearchLimits{Timeout: time.Hour, MaxNodes: serverLimits.MaxNodes + 1, MaxDepth: serverLimits.MaxDepth * 2, MaxQueue: serverLimits.MaxQueue * 10}, serverLimits},
+		{"negative", SearchLimits{Timeout: -time.Second, MaxNodes: -1, MaxDepth: -1, MaxQueue: -1}, SearchLimits{Timeout: serverLimits.Timeout}},
+	}
+	for _, tt := range tests {
+		if got := clampLimits(tt.req); got != tt.want {
+			t.Errorf("%s: clampLimits(%+v) = %+v, want %+v", tt.name, tt.req, got, tt.want)
+		}
+	}
+
+	// Permintaan client melewati clampLimits sebelum sampai ke solver
+	req := RequestData{TimeoutMs: int(time.Hour / time.Millisecond), MaxNodes: 1 << 30, MaxDepth: 1 << 20, MaxQueue: 1 << 30}
+	if got := req.limits(); got != serverLimits {
+		t.Errorf("RequestData.limits() = %+v, want server caps %+v", got, serverLimits)
+	}
+}
+
+// TestSolversStopAtMaxNodes: Chain 19 punya lebih dari sejuta pohon, jadi setiap solver pasti kehabisan
+// node lebih dulu. Chain 5 cukup dangkal untuk menemukan sebagian pohonnya sebelum budget habis, dan
+// pohon itu harus lengkap sampai elemen dasar.
+func TestSolversStopAtMaxNodes(t *testing.T) {
+	graph := newTestGraph(t, append(append([]Element{}, testElements...), chainElements(20)...))
+	ctx := context.Background()
+
+	var complete func(tree TreeNode) bool
+	complete = func(tree TreeNode) bool {
+		if len(tree.Children) == 0 {
+			return isBasicElement(tree.Name)
+		}
+		return len(tree.Children) == 2 && complete(tree.Children[0]) && complete(tree.Children[1])
+	}
+
+	for _, name := range solverNames() {
+		solver, _ := lookupSolver(name)
+		result := solver.Solve(ctx, graph, "chain 19", SolverOptions{MaxRecipes: 1000, Limits: SearchLimits{MaxNodes: 20}, Deterministic: true})
+		if result.TruncatedBy != truncatedByMaxNodes {
+			t.Errorf("%s chain 19: truncatedBy = %q, want %q", name, result.TruncatedBy, truncatedByMaxNodes)
+		}
+		if len(result.Trees) >= 1000 {
+			t.Errorf("%s chain 19: returned %d trees despite the node budget", name, len(result.Trees))
+		}
+
+		result = solver.Solve(ctx, graph, "chain 5", SolverOptions{MaxRecipes: 1000, Limits: SearchLimits{MaxNodes: 100}, Deterministic: true})
+		if len(result.Trees) == 0 {
+			t.Errorf("%s chain 5: no partial trees (truncatedBy %q)", name, result.TruncatedBy)
+		}
+		for _, tree := range result.Trees {
+			if !complete(tree) {
+				t.Errorf("%s chain 5: incomplete tree %+v", name, tree)
+				break
+			}
+		}
+	}
+}
+
+// TestBFSStopsAtMaxQueue: pohon Braid 15 baru lengkap jauh setelah queue penuh. Setelah queue melebihi
+// maxQueue, BFS hanya boleh mengambil satu queue penuh lagi lalu berhenti dengan truncatedBy "maxQueue",
+// bukan terus memangkas queue sampai timeout.
+func TestBFSStopsAtMaxQueue(t *testing.T) {
+	graph := newTestGraph(t, append(append([]Element{}, testElements...), braidElements(16)...))
+	solver, _ := lookupSolver("BFS")
+	const maxQueue = 64
+
+	for _, deterministic := range []bool{true, false} {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		start := time.Now()
+		result := solver.Solve(ctx, graph, "braid 15", SolverOptions{MaxRecipes: 5, Limits: SearchLimits{Timeout: 10 * time.Second, MaxQueue: maxQueue}, Deterministic: deterministic})
+		elapsed := time.Since(start)
+		cancel()
+
+		if result.TruncatedBy != truncatedByMaxQueue {
+			t.Errorf("deterministic=%v: truncatedBy = %q, want %q", deterministic, result.TruncatedBy, truncatedByMaxQueue)
+		}
+		if elapsed > time.Second {
+			t.Errorf("deterministic=%v: took %v to stop after the queue overflowed", deterministic, elapsed)
+		}
+		// Satu batch worker bisa masih berjalan saat batas tercapai
+		if limit := 2*maxQueue + batchSize; result.NodesVisited > limit {
+			t.Errorf("deterministic=%v: visited %d nodes, want at most %d", deterministic, result.NodesVisited, limit)
+		}
+	}
+}
@@ -0,0 +1,509 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+const cliUsage = `Usage: alchemy-scraper <command> [flags]
+
+Commands:
+  serve                          start the HTTP/WebSocket server (default)
+  search <target>... [flags]     search recipes; several targets give one combined plan
+  scrape [-game g] [-offline]    scrape the Little Alchemy wiki into elements.json
+  stats [-json]                  print dataset statistics
+  validate [-strict] [-json]     check elements.json for problems
+  tiers [-write] [-json]         recompute tiers and report the ones that changed
+  diff <old> <new> [-json]       compare two datasets and print a changelog
+
+Run "alchemy-scraper <command> -h" for the flags of a command.
+`
+
+func main() {
+	cmd, args := "serve", os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		cmd, args = args[0], args[1:]
+	}
+
+	switch cmd {
+	case "serve":
+		runServe(args)
+	case "search":
+		os.Exit(runSearch(args, os.Stdout, os.Stderr))
+	case "scrape":
+		os.Exit(runScrape(args, os.Stdout, os.Stderr))
+	case "stats":
+		os.Exit(runStats(args, os.Stdout, os.Stderr))
+	case "validate":
+		os.Exit(runValidate(args, os.Stdout, os.Stderr))
+	case "tiers":
+		os.Exit(runTiers(args, os.Stdout, os.Stderr))
+	case "diff":
+		os.Exit(runDiff(args, os.Stdout, os.Stderr))
+	case "help":
+		fmt.Print(cliUsage)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", cmd, cliUsage)
+		os.Exit(2)
+	}
+}
+
+func defaultDataPath() string {
+	if v := os.Getenv("ELEMENTS_PATH"); v != "" {
+		return v
+	}
+	return "data/elements.json"
+}
+
+// parseInterspersed mem-parse flag yang boleh berada sebelum maupun sesudah argumen posisi,
+// misalnya "search human --algo dfs"
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// parseExitCode mengubah error dari fs.Parse menjadi exit code. FlagSet sudah mencetak pesan dan
+// daftar flag ke stderr; -h bukan kesalahan.
+func parseExitCode(err error) int {
+	if errors.Is(err, flag.ErrHelp) {
+		return 0
+	}
+	return 2
+}
+
+// runSearch: search <target>... --algo bfs|dfs|bid|shortest --max N --format json|text|dot|mermaid|markdown
+func runSearch(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("search", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	dataPath := fs.String("data", defaultDataPath(), "path to elements.json")
+	algo := fs.String("algo", "bfs", "algorithm: "+strings.ToLower(strings.Join(solverNames(), ", ")))
+	maxRecipes := fs.Int("max", 1, "maximum number of recipe plans")
+	format := fs.String("format", "text", "output format: json, text, dot, mermaid or markdown")
+	layout := fs.String("layout", layoutTree, "layout for dot, mermaid and markdown: tree or dag")
+	objective := fs.String("objective", "", "objective for the shortest algorithm: steps or depth (depth always returns one tree)")
+	inventory := fs.String("inventory", "", "comma-separated elements already owned")
+	timeout := fs.Duration("timeout", 0, "search timeout (default: server limit)")
+	maxNodes := fs.Int("max-nodes", 0, "maximum number of visited nodes")
+	deterministic := fs.Bool("deterministic", true, "return the same recipes in the same order on every run")
+	seed := fs.Int64("seed", 0, "shuffle the exploration order reproducibly (implies -deterministic)")
+	targets, err := parseInterspersed(fs, args)
+	if err != nil {
+		return parseExitCode(err)
+	}
+
+	if len(targets) == 0 {
+		fmt.Fprintln(stderr, "search: missing target")
+		fs.Usage()
+		return 2
+	}
+	if *maxRecipes < 1 {
+		fmt.Fprintf(stderr, "search: -max must be at least 1, got %d\n", *maxRecipes)
+		return 2
+	}
+	switch *format {
+	case "json", "text", exportDOT, exportMermaid, exportMarkdown:
+	default:
+		fmt.Fprintf(stderr, "search: unknown format %q\n", *format)
+		return 2
+	}
+	if *layout != layoutTree && *layout != layoutDAG {
+		fmt.Fprintf(stderr, "search: unknown layout %q\n", *layout)
+		return 2
+	}
+	objectiveName, err := parseObjective(*objective)
+	if err != nil {
+		fmt.Fprintf(stderr, "search: %v\n", err)
+		return 2
+	}
+
+	graph, err := loadRecipeGraph(*dataPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "search: %v\n", err)
+		return 1
+	}
+
+	solver, ok := lookupSolver(*algo)
+	if !ok {
+		fmt.Fprintf(stderr, "search: unknown algorithm %q (available: %s)\n", *algo, strings.Join(solverNames(), ", "))
+		return 2
+	}
+	known, unknown := graph.normalizeTargets(targets)
+	if len(unknown) > 0 {
+		for _, t := range unknown {
+			var names []string
+			for _, s := range graph.suggest(t, 3) {
+				names = append(names, s.Name)
+			}
+			fmt.Fprintf(stderr, "search: unknown element %q (did you mean: %s?)\n", t, strings.Join(names, ", "))
+		}
+		return 1
+	}
+
+	opts := SolverOptions{
+		MaxRecipes:    *maxRecipes,
+		Objective:     objectiveName,
+		Limits:        clampLimits(SearchLimits{Timeout: *timeout, MaxNodes: *maxNodes}),
+		Deterministic: *deterministic,
+		Seed:          *seed,
+	}
+	if *inventory != "" {
+		opts.Inventory = strings.Split(*inventory, ",")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), opts.Limits.Timeout)
+	defer cancel()
+
+	// Satu target memakai solver pilihan; beberapa target selalu memakai rencana gabungan
+	startTime := time.Now()
+	var (
+		trees       []TreeNode
+		steps       [][]RecipeStep
+		nodes       int
+		truncatedBy string
+	)
+	if len(known) == 1 {
+		result := solver.Solve(ctx, graph, known[0], opts)
+		trees, nodes, truncatedBy = result.Trees, result.NodesVisited, result.TruncatedBy
+		for _, tree := range trees {
+			steps = append(steps, orderSteps(treeToSteps(tree), known))
+		}
+	} else {
+		plan := planTargets(ctx, graph, known, opts)
+		nodes, truncatedBy = plan.NodesVisited, plan.TruncatedBy
+		if plan.Found {
+			trees, steps = plan.Trees, [][]RecipeStep{plan.Steps}
+		}
+	}
+	elapsed := time.Since(startTime)
+
+	if truncatedBy != "" {
+		fmt.Fprintf(stderr, "search: stopped early (%s) after %d nodes\n", truncatedBy, nodes)
+	}
+	if len(trees) == 0 {
+		fmt.Fprintf(stderr, "search: no recipe plans found for %s\n", strings.Join(graph.displayNames(known), ", "))
+		return 1
+	}
+
+	switch *format {
+	case "json":
+		sequences := make([][]CraftStep, 0, len(steps))
+		for _, s := range steps {
+			sequences = append(sequences, graph.craftSequence(s))
+		}
+		out, _ := json.MarshalIndent(map[string]interface{}{
+			"targets":        graph.displayNames(known),
+			"algorithm":      solver.Name(),
+			"duration":       formatTime(elapsed.String()),
+			"treeData":       trees,
+			"sequences":      sequences,
+			"nodes":          nodes,
+			"truncatedBy":    truncatedBy,
+			"datasetVersion": graph.Version,
+		}, "", "  ")
+		fmt.Fprintln(stdout, string(out))
+	case "text":
+		for i, s := range steps {
+			if len(steps) > 1 {
+				fmt.Fprintf(stdout, "Plan %d:\n", i+1)
+			}
+			for _, step := range graph.craftSequence(s) {
+				fmt.Fprintln(stdout, step.Text)
+			}
+			if len(s) == 0 {
+				fmt.Fprintf(stdout, "%s needs no crafting\n", strings.Join(graph.displayNames(known), ", "))
+			}
+		}
+	case exportDOT, exportMermaid, exportMarkdown:
+		// Untuk beberapa rencana hanya yang pertama yang dipakai pada layout dag
+		switch {
+		case *format == exportDOT && *layout == layoutDAG:
+			fmt.Fprint(stdout, graph.dagDOT(steps[0], known))
+		case *format == exportDOT:
+			fmt.Fprint(stdout, treeDOT(trees))
+		case *format == exportMermaid && *layout == layoutDAG:
+			fmt.Fprint(stdout, graph.dagMermaid(steps[0], known))
+		case *format == exportMermaid:
+			fmt.Fprint(stdout, treeMermaid(trees))
+		case *layout == layoutDAG:
+			fmt.Fprint(stdout, graph.dagMarkdown(steps[0], known))
+		default:
+			fmt.Fprint(stdout, treeMarkdown(trees))
+		}
+	}
+	return 0
+}
+
+func runScrape(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("scrape", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	opts := ScrapeOptions{}
+	fs.StringVar(&opts.Game, "game", gameLA2, "dataset to scrape: la2, la1 or mm (Little Alchemy 2 with Myths and Monsters)")
+	fs.StringVar(&opts.OutFile, "out", "", "output file (default elements.json, elements_la1.json or elements_mm.json)")
+	fs.StringVar(&opts.CacheDir, "cache", "scrape-cache", "directory for cached wiki pages")
+	fs.BoolVar(&opts.Offline, "offline", false, "only parse pages already in the cache")
+	fs.IntVar(&opts.Concurrency, "concurrency", 3, "number of pages fetched at once")
+	fs.IntVar(&opts.Retries, "retries", 5, "retries on 429, 5xx and network errors")
+	fs.BoolVar(&opts.AllowPartial, "allow-partial", false, "write the output even if some pages failed")
+	fs.BoolVar(&opts.Force, "force", false, "write the output even if it fails validation")
+	fs.StringVar(&opts.AssetDir, "assets", "", "directory for element images (default: assets next to the output)")
+	fs.StringVar(&opts.Previous, "previous", "", "dataset to diff against, or \"none\" (default: the dataset the server uses for -game)")
+	ignore := fs.String("ignore", "", "comma-separated validation codes that do not block writing the output")
+	if err := fs.Parse(args); err != nil {
+		return parseExitCode(err)
+	}
+
+	var err error
+	if opts.Ignore, err = parseIgnoreCodes(*ignore); err != nil {
+		fmt.Fprintf(stderr, "scrape: -ignore: %v\n", err)
+		return 2
+	}
+
+	if err := Scraping(opts); err != nil {
+		fmt.Fprintf(stderr, "scrape: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// DatasetStats adalah ringkasan dataset untuk perintah stats
+type DatasetStats struct {
+	Version            string         `json:"datasetVersion"`
+	Elements           int            `json:"elements"`
+	Recipes            int            `json:"recipes"`
+	MaxTier            int            `json:"maxTier"`
+	Tiers              map[int]int    `json:"tiers"`
+	NoRecipes          int            `json:"noRecipes"`
+	UnknownIngredients int            `json:"unknownIngredients"`
+	MostUsed           []ElementUsage `json:"mostUsed"`
+	MostRecipeTrees    ElementUsage   `json:"mostRecipeTrees"`
+}
+
+type ElementUsage struct {
+	Name  string `json:"name"`
+	Count string `json:"count"`
+}
+
+func (g *RecipeGraph) stats() DatasetStats {
+	st := DatasetStats{Version: g.Version, Elements: len(g.Elements), Tiers: make(map[int]int)}
+
+	unknown := make(map[string]bool)
+	for _, name := range g.Names {
+		tier := g.Tiers[name]
+		st.Tiers[tier]++
+		st.MaxTier = max(st.MaxTier, tier)
+		st.Recipes += len(g.Recipes[name])
+		if len(g.Recipes[name]) == 0 && !isBasicElement(name) {
+			st.NoRecipes++
+		}
+		for _, recipe := range g.Recipes[name] {
+			for _, ing := range recipe {
+				if _, ok := g.Elements[ing]; !ok {
+					unknown[ing] = true
+				}
+			}
+		}
+		if c := g.RecipeCount(name); st.MostRecipeTrees.Name == "" || c.Cmp(g.RecipeCount(canonicalName(st.MostRecipeTrees.Name))) > 0 {
+			st.MostRecipeTrees = ElementUsage{Name: g.displayName(name), Count: formatCount(c)}
+		}
+	}
+	st.UnknownIngredients = len(unknown)
+
+	used := make([]string, 0, len(g.UsedIn))
+	for ing := range g.UsedIn {
+		if _, ok := g.Elements[ing]; ok {
+			used = append(used, ing)
+		}
+	}
+	sort.Slice(used, func(i, j int) bool {
+		if len(g.UsedIn[used[i]]) != len(g.UsedIn[used[j]]) {
+			return len(g.UsedIn[used[i]]) > len(g.UsedIn[used[j]])
+		}
+		return used[i] < used[j]
+	})
+	for _, ing := range used[:min(5, len(used))] {
+		st.MostUsed = append(st.MostUsed, ElementUsage{Name: g.displayName(ing), Count: fmt.Sprint(len(g.UsedIn[ing]))})
+	}
+	return st
+}
+
+func runStats(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("stats", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	dataPath := fs.String("data", defaultDataPath(), "path to elements.json")
+	asJSON := fs.Bool("json", false, "print as JSON")
+	if err := fs.Parse(args); err != nil {
+		return parseExitCode(err)
+	}
+
+	graph, err := loadRecipeGraph(*dataPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "stats: %v\n", err)
+		return 1
+	}
+	st := graph.stats()
+
+	if *asJSON {
+		out, _ := json.MarshalIndent(st, "", "  ")
+		fmt.Fprintln(stdout, string(out))
+		return 0
+	}
+
+	fmt.Fprintf(stdout, "Dataset %s (version %s)\n", *dataPath, st.Version)
+	fmt.Fprintf(stdout, "Elements:            %d\n", st.Elements)
+	fmt.Fprintf(stdout, "Recipes:             %d\n", st.Recipes)
+	fmt.Fprintf(stdout, "Without recipes:     %d\n", st.NoRecipes)
+	fmt.Fprintf(stdout, "Unknown ingredients: %d\n", st.UnknownIngredients)
+	fmt.Fprintf(stdout, "Most recipe trees:   %s (%s)\n", st.MostRecipeTrees.Name, st.MostRecipeTrees.Count)
+	fmt.Fprintln(stdout, "Most used ingredients:")
+	for _, u := range st.MostUsed {
+		fmt.Fprintf(stdout, "  %-20s %s\n", u.Name, u.Count)
+	}
+	fmt.Fprintln(stdout, "Elements per tier:")
+	tiers := make([]int, 0, len(st.Tiers))
+	for t := range st.Tiers {
+		tiers = append(tiers, t)
+	}
+	sort.Ints(tiers)
+	for _, t := range tiers {
+		fmt.Fprintf(stdout, "  %3d: %d\n", t, st.Tiers[t])
+	}
+	return 0
+}
+
+// runValidate memeriksa dataset dan mencetak laporan; exit code 1 jika ada error.
+// Dengan -strict, peringatan juga menghasilkan exit code bukan nol.
+func runValidate(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("validate", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	dataPath := fs.String("data", defaultDataPath(), "path to elements.json")
+	strict := fs.Bool("strict", false, "treat warnings as errors")
+	asJSON := fs.Bool("json", false, "print the report as JSON")
+	ignore := fs.String("ignore", "", "comma-separated issue codes to leave out of the report, e.g. unknown-ingredient")
+	if err := fs.Parse(args); err != nil {
+		return parseExitCode(err)
+	}
+
+	codes, err := parseIgnoreCodes(*ignore)
+	if err != nil {
+		fmt.Fprintf(stderr, "validate: -ignore: %v\n", err)
+		return 2
+	}
+	graph, err := loadRecipeGraph(*dataPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "validate: %v\n", err)
+		return 1
+	}
+	report := graph.Validation.ignore(codes)
+
+	if *asJSON {
+		out, _ := json.MarshalIndent(report, "", "  ")
+		fmt.Fprintln(stdout, string(out))
+	} else {
+		for _, issue := range report.Issues {
+			fmt.Fprintf(stdout, "%s: [%s] %s: %s\n", issue.Severity, issue.Code, issue.Element, issue.Message)
+		}
+	}
+	fmt.Fprintf(stderr, "%s: %d elements, %d errors, %d warnings, %d ignored\n", *dataPath, report.Elements, report.Errors, report.Warnings, report.Ignored)
+	if !report.OK() || (*strict && report.Warnings > 0) {
+		return 1
+	}
+	return 0
+}
+
+// runTiers menghitung ulang tier dataset dan melaporkan elemen yang tier-nya berubah.
+// File hanya ditulis ulang dengan -write.
+func runTiers(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("tiers", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	dataPath := fs.String("data", defaultDataPath(), "path to elements.json")
+	write := fs.Bool("write", false, "save the recomputed tiers back to the data file")
+	asJSON := fs.Bool("json", false, "print the changes as JSON")
+	if err := fs.Parse(args); err != nil {
+		return parseExitCode(err)
+	}
+
+	elements, err := readElements(*dataPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "tiers: %v\n", err)
+		return 1
+	}
+
+	changes := recomputeTiers(elements)
+	if *asJSON {
+		out, _ := json.MarshalIndent(changes, "", "  ")
+		fmt.Fprintln(stdout, string(out))
+	} else {
+		for _, c := range changes {
+			fmt.Fprintf(stdout, "%-24s %4d -> %d\n", c.Name, c.Old, c.New)
+		}
+	}
+	fmt.Fprintf(stderr, "%s: %d of %d tiers changed\n", *dataPath, len(changes), len(elements))
+
+	if *write && len(changes) > 0 {
+		if err := saveJSON(elements, *dataPath); err != nil {
+			fmt.Fprintf(stderr, "tiers: %v\n", err)
+			return 1
+		}
+		fmt.Fprintf(stderr, "Saved %s\n", *dataPath)
+	}
+	return 0
+}
+
+// runDiff: diff old.json new.json [-json]. Exit code 1 jika ada perubahan, seperti diff biasa.
+func runDiff(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	asJSON := fs.Bool("json", false, "print the diff as JSON instead of a Markdown changelog")
+	title := fs.String("title", "", "changelog heading (default: \"<old> → <new>\")")
+	files, err := parseInterspersed(fs, args)
+	if err != nil {
+		return parseExitCode(err)
+	}
+	if len(files) != 2 {
+		fmt.Fprintln(stderr, "usage: diff <old.json> <new.json> [-json] [-title text]")
+		return 2
+	}
+
+	oldElements, err := readElements(files[0])
+	if err != nil {
+		fmt.Fprintf(stderr, "diff: %v\n", err)
+		return 2
+	}
+	newElements, err := readElements(files[1])
+	if err != nil {
+		fmt.Fprintf(stderr, "diff: %v\n", err)
+		return 2
+	}
+
+	d := diffDatasets(oldElements, newElements)
+	if *asJSON {
+		out, _ := json.MarshalIndent(d, "", "  ")
+		fmt.Fprintln(stdout, string(out))
+	} else {
+		if *title == "" {
+			*title = files[0] + " → " + files[1]
+		}
+		fmt.Fprint(stdout, d.changelog(*title))
+	}
+	if d.Empty() {
+		return 0
+	}
+	return 1
+}
@@ -0,0 +1,309 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// runCLI menjalankan satu subcommand seperti dari command line dan mengembalikan exit code dan keluarannya
+func runCLI(run func([]string, io.Writer, io.Writer) int, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(args, &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+// cliDataset menyimpan testElements dengan tier yang benar ke folder sementara
+func cliDataset(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "elements.json")
+	writeTestDataset(t, path, testElements)
+	return path
+}
+
+func TestCLISearch(t *testing.T) {
+	data := cliDataset(t)
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantCode   int
+		wantStdout []string
+		wantStderr []string
+	}{
+		{
+			name:       "text",
+			args:       []string{"wall", "--data", data, "--algo", "dfs"},
+			wantStdout: []string{"1. Earth + Water → Mud\n", "Brick + Brick → Wall\n"},
+		},
+		{
+			name:       "several plans",
+			args:       []string{"--data", data, "wall", "--max", "2", "--algo", "shortest"},
+			wantStdout: []string{"Plan 1:\n", "Plan 2:\n"},
+		},
+		{
+			name:       "combined plan",
+			args:       []string{"--data", data, "brick", "stone"},
+			wantStdout: []string{"→ Brick\n", "→ Stone\n"},
+		},
+		{
+			name:       "dot",
+			args:       []string{"--data", data, "--format", "dot", "brick"},
+			wantStdout: []string{"digraph"},
+		},
+		{
+			name:       "mermaid dag",
+			args:       []string{"--data", data, "--format", "mermaid", "--layout", "dag", "brick"},
+			wantStdout: []string{"graph TD"},
+		},
+		{
+			name:       "basic element",
+			args:       []string{"--data", data, "fire"},
+			wantStdout: []string{"Fire needs no crafting\n"},
+		},
+		{
+			name:       "unknown target",
+			args:       []string{"--data", data, "wal"},
+			wantCode:   1,
+			wantStderr: []string{`unknown element "wal"`, "did you mean: Wall"},
+		},
+		{
+			name:       "missing dataset",
+			args:       []string{"--data", filepath.Join(t.TempDir(), "missing.json"), "wall"},
+			wantCode:   1,
+			wantStderr: []string{"missing.json"},
+		},
+		{
+			name:       "unknown algorithm",
+			args:       []string{"--data", data, "--algo", "astar", "wall"},
+			wantCode:   2,
+			wantStderr: []string{`unknown algorithm "astar"`, "BFS, BID, DFS, SHORTEST"},
+		},
+		{
+			name:       "unknown format",
+			args:       []string{"--data", data, "--format", "yaml", "wall"},
+			wantCode:   2,
+			wantStderr: []string{`unknown format "yaml"`},
+		},
+		{
+			name:       "unknown layout",
+			args:       []string{"--data", data, "--layout", "flat", "wall"},
+			wantCode:   2,
+			wantStderr: []string{`unknown layout "flat"`},
+		},
+		{
+			name:       "unknown objective",
+			args:       []string{"--data", data, "--algo", "shortest", "--objective", "deep", "wall"},
+			wantCode:   2,
+			wantStderr: []string{"deep"},
+		},
+		{
+			name:       "bad flag value",
+			args:       []string{"--data", data, "--max", "many", "wall"},
+			wantCode:   2,
+			wantStderr: []string{`invalid value "many" for flag -max`},
+		},
+		{
+			name:       "undefined flag",
+			args:       []string{"--data", data, "--fast", "wall"},
+			wantCode:   2,
+			wantStderr: []string{"flag provided but not defined: -fast"},
+		},
+		{
+			name:       "max below one",
+			args:       []string{"--data", data, "--max", "0", "wall"},
+			wantCode:   2,
+			wantStderr: []string{"-max must be at least 1"},
+		},
+		{
+			name:       "missing target",
+			args:       []string{"--data", data},
+			wantCode:   2,
+			wantStderr: []string{"missing target"},
+		},
+		{
+			name:       "help",
+			args:       []string{"-h"},
+			wantStderr: []string{"-algo"},
+		},
+	}
+	for _, tt := range tests {
+		code, stdout, stderr := runCLI(runSearch, tt.args...)
+		if code != tt.wantCode {
+			t.Errorf("%s: exit code %d, want %d\nstderr: %s", tt.name, code, tt.wantCode, stderr)
+		}
+		for _, want := range tt.wantStdout {
+			if !strings.Contains(stdout, want) {
+				t.Errorf("%s: stdout does not contain %q:\n%s", tt.name, want, stdout)
+			}
+		}
+		for _, want := range tt.wantStderr {
+			if !strings.Contains(stderr, want) {
+				t.Errorf("%s: stderr does not contain %q:\n%s", tt.name, want, stderr)
+			}
+		}
+		if tt.wantCode != 0 && stdout != "" {
+			t.Errorf("%s: failed search wrote to stdout:\n%s", tt.name, stdout)
+		}
+	}
+}
+
+func TestCLISearchJSON(t *testing.T) {
+	code, stdout, stderr := runCLI(runSearch, "wall", "--data", cliDataset(t), "--algo", "bfs", "--max", "2", "--format", "json")
+	if code != 0 {
+		t.Fatalf("exit code %d: %s", code, stderr)
+	}
+	var out struct {
+		Targets     []string      `json:"targets"`
+		Algorithm   string        `json:"algorithm"`
+		TreeData    []TreeNode    `json:"treeData"`
+		Sequences   [][]CraftStep `json:"sequences"`
+		TruncatedBy string        `json:"truncatedBy"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &out); err != nil {
+		t.Fatalf("stdout is not JSON: %v\n%s", err, stdout)
+	}
+	if len(out.Targets) != 1 || out.Targets[0] != "Wall" || out.Algorithm != "BFS" {
+		t.Errorf("targets %q, algorithm %q, want [Wall] and BFS", out.Targets, out.Algorithm)
+	}
+	if len(out.TreeData) != 2 || len(out.Sequences) != 2 || out.TruncatedBy != "" {
+		t.Errorf("%d trees, %d sequences, truncatedBy %q, want 2, 2 and none", len(out.TreeData), len(out.Sequences), out.TruncatedBy)
+	}
+}
+
+func TestCLIStats(t *testing.T) {
+	data := cliDataset(t)
+
+	code, stdout, _ := runCLI(runStats, "-data", data, "-json")
+	var st DatasetStats
+	if err := json.Unmarshal([]byte(stdout), &st); code != 0 || err != nil {
+		t.Fatalf("exit code %d, %v:\n%s", code, err, stdout)
+	}
+	if st.Elements != 9 || st.Recipes != 8 || st.MaxTier != 3 || st.Tiers[0] != 4 || st.NoRecipes != 0 {
+		t.Errorf("stats = %+v, want 9 elements, 8 recipes, max tier 3 and 4 basics", st)
+	}
+
+	code, stdout, _ = runCLI(runStats, "-data", data)
+	if code != 0 || !strings.Contains(stdout, "Elements:            9\n") || !strings.Contains(stdout, "Recipes:             8\n") {
+		t.Errorf("text stats, exit code %d:\n%s", code, stdout)
+	}
+
+	if code, _, stderr := runCLI(runStats, "-data", data, "-verbose"); code != 2 || !strings.Contains(stderr, "-verbose") {
+		t.Errorf("undefined flag: exit code %d, stderr %s", code, stderr)
+	}
+}
+
+func TestCLIValidate(t *testing.T) {
+	good := cliDataset(t)
+	bad := filepath.Join(t.TempDir(), "elements.json")
+	writeTestDataset(t, bad, append(append([]Element{}, testElements...),
+		Element{Name: "Geyser", Recipes: [][]string{{"Steam", "Earth"}, {"Mud", "Fire"}}}))
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantCode   int
+		wantStdout string
+		wantStderr string
+	}{
+		{"valid", []string{"-data", good}, 0, "", "0 errors"},
+		{"unknown ingredient", []string{"-data", bad}, 1, "error: [unknown-ingredient] Geyser:", "1 errors"},
+		{"ignored", []string{"-data", bad, "-ignore", "unknown-ingredient"}, 0, "", "ignored"},
+		{"unknown ignore code", []string{"-data", bad, "-ignore", "unknown-ingrediant"}, 2, "", "unknown validation code"},
+		{"missing dataset", []string{"-data", filepath.Join(t.TempDir(), "missing.json")}, 1, "", "missing.json"},
+		{"bad flag value", []string{"-data", good, "-strict=maybe"}, 2, "", "invalid boolean value"},
+	}
+	for _, tt := range tests {
+		code, stdout, stderr := runCLI(runValidate, tt.args...)
+		if code != tt.wantCode || !strings.Contains(stdout, tt.wantStdout) || !strings.Contains(stderr, tt.wantStderr) {
+			t.Errorf("%s: exit code %d, want %d\nstdout: %s\nstderr: %s", tt.name, code, tt.wantCode, stdout, stderr)
+		}
+	}
+
+	code, stdout, _ := runCLI(runValidate, "-data", bad, "-json")
+	var report ValidationReport
+	if err := json.Unmarshal([]byte(stdout), &report); err != nil || code != 1 {
+		t.Fatalf("-json: exit code %d, %v:\n%s", code, err, stdout)
+	}
+	if report.Errors != 1 || report.Warnings != 0 || len(report.Issues) != 1 || report.Issues[0].Code != "unknown-ingredient" {
+		t.Errorf("-json report = %+v, want one unknown-ingredient error", report)
+	}
+}
+
+func TestCLITiers(t *testing.T) {
+	data := cliDataset(t)
+	if code, stdout, stderr := runCLI(runTiers, "-data", data); code != 0 || stdout != "" || !strings.Contains(stderr, "0 of 9 tiers changed") {
+		t.Errorf("correct tiers: exit code %d\nstdout: %s\nstderr: %s", code, stdout, stderr)
+	}
+
+	// Tier Mud ditulis salah langsung, tanpa recomputeTiers dari writeTestDataset
+	elements, _ := readElements(data)
+	for i := range elements {
+		if elements[i].Name == "Mud" {
+			elements[i].Tier = 5
+		}
+	}
+	if err := saveJSON(elements, data); err != nil {
+		t.Fatal(err)
+	}
+
+	code, stdout, _ := runCLI(runTiers, "-data", data, "-json")
+	var changes []TierChange
+	if err := json.Unmarshal([]byte(stdout), &changes); code != 0 || err != nil {
+		t.Fatalf("-json: exit code %d, %v:\n%s", code, err, stdout)
+	}
+	if len(changes) != 1 || changes[0] != (TierChange{Name: "Mud", Old: 5, New: 1}) {
+		t.Errorf("changes = %+v, want Mud 5 -> 1", changes)
+	}
+	if saved, _ := readElements(data); saved[4].Tier != 5 {
+		t.Errorf("without -write the file changed: Mud tier %d", saved[4].Tier)
+	}
+
+	code, stdout, _ = runCLI(runTiers, "-data", data, "-write")
+	if code != 0 || !strings.Contains(stdout, "Mud") {
+		t.Errorf("-write: exit code %d:\n%s", code, stdout)
+	}
+	if saved, _ := readElements(data); saved[4].Tier != 1 {
+		t.Errorf("-write did not save Mud tier 1, got %d", saved[4].Tier)
+	}
+}
+
+func TestCLIDiff(t *testing.T) {
+	oldPath := cliDataset(t)
+	newPath := filepath.Join(t.TempDir(), "new.json")
+	writeTestDataset(t, newPath, append(append([]Element{}, testElements...),
+		Element{Name: "Clay", Recipes: [][]string{{"Mud", "Air"}}}))
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantCode   int
+		wantStdout string
+		wantStderr string
+	}{
+		{"unchanged", []string{oldPath, oldPath, "-title", "Same"}, 0, "## Same\n\nNo changes.\n", ""},
+		{"changed", []string{oldPath, newPath, "-title", "v2"}, 1, "- **Clay** (tier 2): Air + Mud\n", ""},
+		{"default title", []string{oldPath, newPath}, 1, "## " + oldPath + " → " + newPath, ""},
+		{"json", []string{"-json", oldPath, newPath}, 1, `"addedElements": [`, ""},
+		{"one file", []string{oldPath}, 2, "", "usage: diff"},
+		{"missing file", []string{oldPath, filepath.Join(t.TempDir(), "missing.json")}, 2, "", "missing.json"},
+		{"undefined flag", []string{"-markdown", oldPath, newPath}, 2, "", "-markdown"},
+	}
+	for _, tt := range tests {
+		code, stdout, stderr := runCLI(runDiff, tt.args...)
+		if code != tt.wantCode || !strings.Contains(stdout, tt.wantStdout) || !strings.Contains(stderr, tt.wantStderr) {
+			t.Errorf("%s: exit code %d, want %d\nstdout: %s\nstderr: %s", tt.name, code, tt.wantCode, stdout, stderr)
+		}
+	}
+}
+
+// TestCLIDefaultDataPath: subcommand tanpa -data membaca ELEMENTS_PATH
+func TestCLIDefaultDataPath(t *testing.T) {
+	t.Setenv("ELEMENTS_PATH", cliDataset(t))
+	if code, stdout, stderr := runCLI(runSearch, "mud"); code != 0 || stdout != "1. Earth + Water → Mud\n" {
+		t.Errorf("exit code %d\nstdout: %s\nstderr: %s", code, stdout, stderr)
+	}
+}
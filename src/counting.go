@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+)
+
+// countRecipeTrees menghitung banyaknya pohon resep berbeda untuk setiap elemen dengan
+// dynamic programming. Elemen dasar dan elemen tanpa resep dihitung satu, dan resep hanya dihitung
+// jika lolos isValidRecipe, batasan tier yang sama dengan yang dipakai solver.
+// Selama semua tier sudah dihitung, tier selalu turun sehingga graph-nya DAG dan setiap elemen cukup
+// dihitung sekali. Tier yang belum dihitung (-1) bisa membentuk siklus; resep yang kembali ke elemen
+// yang sedang dihitung tidak menghasilkan pohon berhingga, jadi bernilai nol.
+func countRecipeTrees(graph *RecipeGraph) map[string]*big.Int {
+	counts := make(map[string]*big.Int, len(graph.Elements))
+	counting := make(map[string]bool)
+
+	var count func(name string) *big.Int
+	count = func(name string) *big.Int {
+		if c, ok := counts[name]; ok {
+			return c
+		}
+		if counting[name] {
+			return big.NewInt(0)
+		}
+		elem, ok := graph.Elements[name]
+		if !ok {
+			return big.NewInt(0)
+		}
+		if isBasicElement(name) || len(elem.Recipes) == 0 {
+			counts[name] = big.NewInt(1)
+			return counts[name]
+		}
+
+		counting[name] = true
+		total := new(big.Int)
+		for _, recipe := range graph.Recipes[name] {
+			if !isValidRecipe(recipe[0], recipe[1], elem.Tier, graph.Elements, nil) {
+				continue
+			}
+			total.Add(total, new(big.Int).Mul(count(recipe[0]), count(recipe[1])))
+		}
+		delete(counting, name)
+		counts[name] = total
+		return total
+	}
+
+	for _, name := range graph.Names {
+		count(name)
+	}
+	return counts
+}
+
+// RecipeCount mengembalikan jumlah pohon resep untuk elemen, atau nil jika elemen tidak ada
+func (g *RecipeGraph) RecipeCount(name string) *big.Int {
+	return g.Counts[canonicalName(name)]
+}
+
+// formatCount menampilkan angka besar secara ringkas, misalnya "4.2×10^18"
+func formatCount(n *big.Int) string {
+	if n == nil {
+		return "0"
+	}
+	s := n.String()
+	if len(s) <= 6 {
+		return s
+	}
+	// Text('e') menghasilkan misalnya "4.2e+18"
+	parts := strings.SplitN(new(big.Float).SetInt(n).Text('e', 1), "e", 2)
+	return fmt.Sprintf("%s×10^%s", parts[0], strings.TrimLeft(parts[1], "+0"))
+}
+
+// handleCountRecipes: GET /api/elements/{name}/count
+func handleCountRecipes(datasets *Datasets, w http.ResponseWriter, r *http.Request) {
+	graph, ok := datasets.requestGraph(w, r.URL.Query().Get("dataset"))
+	if !ok {
+		return
+	}
+	name, _ := graph.Resolve(r.PathValue("name"))
+	elem, ok := graph.Elements[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Unknown element %q", r.PathValue("name"))
+		return
+	}
+
+	count := graph.RecipeCount(name)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"name":           elem.Name,
+		"count":          count.String(),
+		"approx":         formatCount(count),
+		"digits":         len(count.String()),
+		"datasetVersion": graph.Version,
+	})
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestCountRecipeTrees(t *testing.T) {
+	elements := append(append([]Element{}, testElements...),
+		Element{Name: "Steam", Recipes: [][]string{{"Fire", "Water"}, {"Air", "Water"}}},
+	)
+	elements = append(elements, chainElements(70)...)
+	graph := newTestGraph(t, elements)
+
+	// 2^70 tidak muat di uint64
+	overflow := new(big.Int).Lsh(big.NewInt(1), 70)
+
+	tests := []struct {
+		name string
+		want *big.Int
+	}{
+		{"fire", big.NewInt(1)},
+		// Dua resep dari elemen dasar
+		{"steam", big.NewInt(2)},
+		// Lava+Air dan Mud+Fire
+		{"stone", big.NewInt(2)},
+		// Stone+Mud tidak dihitung karena Stone setier dengan Brick
+		{"brick", big.NewInt(1)},
+		// Brick+Brick (1x1) ditambah Stone+Stone (2x2): Stone dipakai di kedua sisi
+		{"wall", big.NewInt(5)},
+		// Chain k punya 2^(k+1) pohon
+		{"chain 69", overflow},
+	}
+	for _, tt := range tests {
+		got := graph.RecipeCount(tt.name)
+		if got == nil || got.Cmp(tt.want) != 0 {
+			t.Errorf("RecipeCount(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+	if graph.RecipeCount("chain 69").IsUint64() {
+		t.Errorf("RecipeCount(\"chain 69\") should not fit in uint64")
+	}
+}
+
+// TestCountRecipeTreesUncomputedTiers memakai tier -1 yang belum dihitung. isValidRecipe menganggap
+// resep dengan bahan bertier -1 valid, jadi jumlah pohonnya harus ikut dihitung.
+func TestCountRecipeTreesUncomputedTiers(t *testing.T) {
+	elements := append([]Element{}, testElements...)
+	recomputeTiers(elements)
+	elements = append(elements,
+		Element{Name: "Ash", Tier: -1},
+		Element{Name: "Soot", Recipes: [][]string{{"Ash", "Air"}, {"Ash", "Ash"}}, Tier: -1},
+		Element{Name: "Smoke", Recipes: [][]string{{"Soot", "Fire"}}, Tier: 2},
+		// Siklus lewat tier -1 tidak boleh membuat hitungan berputar tanpa akhir
+		Element{Name: "Loop", Recipes: [][]string{{"Loop", "Air"}, {"Ash", "Air"}}, Tier: -1},
+	)
+	graph := newRecipeGraph(normalizeElements(elements, nil), nil)
+
+	tests := []struct {
+		name string
+		want int64
+	}{
+		{"soot", 2},
+		{"smoke", 2},
+		{"loop", 1},
+	}
+	for _, tt := range tests {
+		if got := graph.RecipeCount(tt.name); got == nil || got.Cmp(big.NewInt(tt.want)) != 0 {
+			t.Errorf("RecipeCount(%q) = %v, want %d", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DatasetStore menyimpan RecipeGraph yang sedang aktif dan bisa di-reload tanpa restart.
+// Pencarian yang sedang berjalan tetap memakai graph lama karena graph tidak pernah diubah,
+// hanya pointer-nya yang diganti.
+type DatasetStore struct {
+	path    string
+	current atomic.Pointer[RecipeGraph]
+	// strict menolak dataset yang punya error validasi; tanpa strict error hanya dicatat di log
+	strict bool
+	// ignore adalah code validasi yang tidak dihitung, lihat ValidationReport.ignore
+	ignore []string
+
+	reloadMutex sync.Mutex
+	// files adalah datasetFiles saat terakhir dimuat, dibandingkan oleh Watch
+	files string
+}
+
+func newDatasetStore(path string, strict bool, ignore []string) (*DatasetStore, error) {
+	s := &DatasetStore{path: path, strict: strict, ignore: ignore}
+	if _, err := s.Reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *DatasetStore) Graph() *RecipeGraph {
+	return s.current.Load()
+}
+
+// Reload membaca ulang file dataset. Jika file tidak valid, graph lama tetap dipakai.
+func (s *DatasetStore) Reload() (*RecipeGraph, error) {
+	s.reloadMutex.Lock()
+	defer s.reloadMutex.Unlock()
+
+	files, err := datasetFiles(s.path)
+	if err != nil {
+		return nil, err
+	}
+
+	// files dicatat walau gagal supaya Watch tidak mencoba file rusak yang sama berulang kali
+	s.files = files
+
+	g, err := loadRecipeGraph(s.path)
+	if err != nil {
+		return nil, err
+	}
+	if v := g.Validation.ignore(s.ignore); !v.OK() {
+		if s.strict {
+			return nil, fmt.Errorf("%s failed validation with %d errors (run \"validate\" for the report)", s.path, v.Errors)
+		}
+		log.Printf("Warning: %s has %d validation errors and %d warnings (run \"validate\" for the report)\n", s.path, v.Errors, v.Warnings)
+	}
+
+	if old := s.current.Swap(g); old != nil && old.Version != g.Version {
+		log.Printf("Dataset reloaded: %s -> %s (%d elements)\n", old.Version, g.Version, len(g.Elements))
+	}
+	return g, nil
+}
+
+// datasetFiles meringkas waktu modifikasi elements.json dan aliases.json di sampingnya.
+// aliases.json boleh tidak ada, tetapi membuat atau menghapusnya juga mengubah hasilnya.
+func datasetFiles(path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	files := info.ModTime().String()
+	if info, err := os.Stat(filepath.Join(filepath.Dir(path), aliasFile)); err == nil {
+		files += "|" + info.ModTime().String()
+	}
+	return files, nil
+}
+
+// Watch memeriksa waktu modifikasi elements.json dan aliases.json secara berkala dan me-reload jika berubah
+func (s *DatasetStore) Watch(interval time.Duration, done <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			files, err := datasetFiles(s.path)
+			if err != nil {
+				continue
+			}
+
+			s.reloadMutex.Lock()
+			changed := files != s.files
+			s.reloadMutex.Unlock()
+
+			if changed {
+				if _, err := s.Reload(); err != nil {
+					log.Printf("Dataset reload failed, keeping version %s: %v\n", s.Graph().Version, err)
+				}
+			}
+		}
+	}
+}
+
+// Datasets adalah kumpulan DatasetStore bernama, misalnya "la2", "la1" dan "mm".
+// Request tanpa nama dataset memakai dataset default.
+type Datasets struct {
+	stores      map[string]*DatasetStore
+	names       []string
+	defaultName string
+}
+
+// parseDatasetSpecs membaca daftar "nama=path,nama=path" dari flag -datasets
+func parseDatasetSpecs(spec string) (map[string]string, error) {
+	paths := make(map[string]string)
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, path, ok := strings.Cut(part, "=")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !ok || name == "" || strings.TrimSpace(path) == "" {
+			return nil, fmt.Errorf("invalid dataset %q, use name=path", part)
+		}
+		paths[name] = strings.TrimSpace(path)
+	}
+	return paths, nil
+}
+
+func newDatasets(defaultName string, paths map[string]string, strict bool, ignore []string) (*Datasets, error) {
+	d := &Datasets{stores: make(map[string]*DatasetStore, len(paths)), defaultName: defaultName}
+	for name, path := range paths {
+		store, err := newDatasetStore(path, strict, ignore)
+		if err != nil {
+			return nil, fmt.Errorf("dataset %s: %w", name, err)
+		}
+		d.stores[name] = store
+		d.names = append(d.names, name)
+	}
+	sort.Strings(d.names)
+	if _, ok := d.stores[defaultName]; !ok {
+		return nil, fmt.Errorf("default dataset %q is not configured", defaultName)
+	}
+	return d, nil
+}
+
+// Store mengembalikan dataset dengan nama tersebut, atau dataset default jika name kosong
+func (d *Datasets) Store(name string) (*DatasetStore, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		name = d.defaultName
+	}
+	store, ok := d.stores[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown dataset %q, available: %s", name, strings.Join(d.names, ", "))
+	}
+	return store, nil
+}
+
+// requestGraph mengambil graph dataset yang diminta, atau menulis response 404 jika tidak ada
+func (d *Datasets) requestGraph(w http.ResponseWriter, name string) (*RecipeGraph, bool) {
+	store, err := d.Store(name)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, map[string]interface{}{
+			"error":    err.Error(),
+			"datasets": d.names,
+		})
+		return nil, false
+	}
+	return store.Graph(), true
+}
+
+// handleListDatasets: GET /api/datasets
+func handleListDatasets(datasets *Datasets, w http.ResponseWriter, r *http.Request) {
+	list := make([]map[string]interface{}, 0, len(datasets.names))
+	for _, name := range datasets.names {
+		g := datasets.stores[name].Graph()
+		list = append(list, map[string]interface{}{
+			"name":           name,
+			"default":        name == datasets.defaultName,
+			"elements":       len(g.Elements),
+			"datasetVersion": g.Version,
+		})
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"datasets": list})
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeTestDataset menyimpan elements dengan tier minimal ke path, seperti yang ditulis scrape
+func writeTestDataset(t *testing.T, path string, elements []Element) {
+	t.Helper()
+	elements = append([]Element{}, elements...)
+	recomputeTiers(elements)
+	if err := saveJSON(elements, path); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// waitForVersion menunggu sampai Watch memuat graph dengan versi selain before
+func waitForVersion(store *DatasetStore, before string) *RecipeGraph {
+	deadline := time.Now().Add(2 * time.Second)
+	for store.Graph().Version == before && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	return store.Graph()
+}
+
+// TestDatasetReloadsElements menulis ulang elements.json selagi Watch berjalan. Graph baru harus
+// dimuat, sementara graph lama yang masih dipegang pencarian tetap utuh dan bisa dipakai.
+func TestDatasetReloadsElements(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "elements.json")
+	writeTestDataset(t, path, testElements)
+
+	store, err := newDatasetStore(path, true, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	old := store.Graph()
+
+	done := make(chan struct{})
+	defer close(done)
+	go store.Watch(10*time.Millisecond, done)
+
+	// Waktu modifikasi harus berubah walau sistem file hanya menyimpan resolusi kasar
+	time.Sleep(20 * time.Millisecond)
+	writeTestDataset(t, path, append(append([]Element{}, testElements...),
+		Element{Name: "Steam", Recipes: [][]string{{"Fire", "Water"}}},
+	))
+
+	graph := waitForVersion(store, old.Version)
+	if graph.Version == old.Version {
+		t.Fatalf("version still %s after rewriting elements.json", old.Version)
+	}
+	if _, ok := graph.Elements["steam"]; !ok {
+		t.Error("reloaded graph has no Steam")
+	}
+
+	if _, ok := old.Elements["steam"]; ok {
+		t.Error("old graph was modified by the reload")
+	}
+	solver, _ := lookupSolver("BFS")
+	if result := solver.Solve(context.Background(), old, "wall", SolverOptions{MaxRecipes: 1, Deterministic: true}); len(result.Trees) != 1 {
+		t.Errorf("search on the old graph: %d trees, want 1", len(result.Trees))
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("temporary file left behind: %v", err)
+	}
+}
+
+// TestDatasetWatchesAliases memastikan aliases.json ikut menentukan versi dataset dan memicu reload,
+// karena alias mengubah hasil Resolve untuk share-link yang sama
+func TestDatasetWatchesAliases(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "elements.json")
+	writeTestDataset(t, path, testElements)
+
+	store, err := newDatasetStore(path, false, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	before := store.Graph().Version
+	if key, ok := store.Graph().Resolve("pebble"); ok {
+		t.Fatalf("pebble resolved to %q before the alias existed", key)
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	go store.Watch(10*time.Millisecond, done)
+
+	if err := os.WriteFile(filepath.Join(dir, aliasFile), []byte(`{"pebble": "Stone"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	graph := waitForVersion(store, before)
+	if graph.Version == before {
+		t.Fatalf("version still %s after writing %s", before, aliasFile)
+	}
+	if key, ok := graph.Resolve("pebble"); !ok || key != "stone" {
+		t.Errorf("Resolve(pebble) = %q, %v, want stone", key, ok)
+	}
+}
@@ -1,50 +1,75 @@
 package main
 
 import (
+	"context"
 	"strings"
 	"sync"
-	"sync/atomic"
 	"time"
+
 	"github.com/gorilla/websocket"
 )
 
 type DFSData struct {
+	budget        *SearchBudget
+	elementMap    map[string]Element
+	leaves        LeafSet
 	initialTarget string
 	maxRecipes    int
+	maxDepth      int
 	cache         map[string][]TreeNode
-	nodeCounter   int64
+	order         searchOrder
 }
 
-func dfsMultiple(target string, maxRecipes int) ([]TreeNode, int) {
-	DFSData := DFSData {
-		initialTarget: strings.ToLower(target),
+func dfsMultiple(budget *SearchBudget, graph *RecipeGraph, target string, maxRecipes int, leaves LeafSet, order searchOrder) ([]TreeNode, int) {
+	DFSData := DFSData{
+		budget:        budget,
+		elementMap:    graph.Elements,
+		leaves:        leaves,
+		initialTarget: canonicalName(target),
 		maxRecipes:    maxRecipes,
-		nodeCounter:   0,
+		maxDepth:      budget.limits.MaxDepth,
+		order:         order,
 	}
 
 	var resultTrees []TreeNode
-	resultTrees = DFSData.dfsRecursive(strings.ToLower(target))
+	resultTrees = DFSData.dfsRecursive(canonicalName(target), 0)
 
 	if maxRecipes > 0 && len(resultTrees) > maxRecipes {
-		return resultTrees[:maxRecipes], int(DFSData.nodeCounter)
+		return resultTrees[:maxRecipes], budget.Nodes()
 	}
-	return resultTrees, int(DFSData.nodeCounter)
+	return resultTrees, budget.Nodes()
 }
 
-func (d *DFSData) dfsRecursive(currElement string) []TreeNode {
-	atomic.AddInt64(&d.nodeCounter, 1)
-	currElement = strings.ToLower(currElement)
+// depthExceeded mencatat pemotongan jika kedalaman rekursi melewati maxDepth (0 berarti tanpa batas)
+func (d *DFSData) depthExceeded(depth int) bool {
+	if d.maxDepth > 0 && depth > d.maxDepth {
+		d.budget.Truncate(truncatedByMaxDepth)
+		return true
+	}
+	return false
+}
+
+func (d *DFSData) dfsRecursive(currElement string, depth int) []TreeNode {
+	budgetLeft := d.budget.Visit()
+	if budgetLeft && d.depthExceeded(depth) {
+		return []TreeNode{}
+	}
+	currElement = canonicalName(currElement)
 
-	elemDetails, exists := elementMap[currElement]
+	elemDetails, exists := d.elementMap[currElement]
 	if !exists {
 		return []TreeNode{}
 	}
 
-	if isBasicElement(elemDetails.Name) {
+	// Daun tetap dikembalikan setelah budget habis supaya resep yang sedang dirangkai tidak hilang
+	if d.leaves.Has(elemDetails.Name) {
 		leafNode := TreeNode{Name: elemDetails.Name}
 		basicTreeList := []TreeNode{leafNode}
 		return basicTreeList
 	}
+	if !budgetLeft {
+		return []TreeNode{}
+	}
 
 	if len(elemDetails.Recipes) == 0 {
 		leafNode := TreeNode{Name: elemDetails.Name}
@@ -56,43 +81,52 @@ func (d *DFSData) dfsRecursive(currElement string) []TreeNode {
 	productTier := elemDetails.Tier
 
 recipePairLoop:
-	for _, recipePair := range elemDetails.Recipes {
+	for _, recipePair := range d.order.recipes(currElement, elemDetails.Recipes) {
+		if d.budget.Stopped() {
+			break
+		}
 		if len(recipePair) != 2 {
 			continue
 		}
-		parent1Name := strings.ToLower(recipePair[0])
-		parent2Name := strings.ToLower(recipePair[1])
+		parent1Name := canonicalName(recipePair[0])
+		parent2Name := canonicalName(recipePair[1])
 
-		elemParent1, p1Exists := elementMap[parent1Name]
-		elemParent2, p2Exists := elementMap[parent2Name]
+		elemParent1, p1Exists := d.elementMap[parent1Name]
+		elemParent2, p2Exists := d.elementMap[parent2Name]
 
 		if !p1Exists || !p2Exists {
 			continue
 		}
-		if elemParent1.Tier >= productTier || elemParent2.Tier >= productTier {
+		if (elemParent1.Tier >= productTier && !d.leaves[parent1Name]) || (elemParent2.Tier >= productTier && !d.leaves[parent2Name]) {
 			continue
 		}
 
 		var subTreesForParent1 []TreeNode
 		var subTreesForParent2 []TreeNode
-		var wg sync.WaitGroup
-		wg.Add(2)
+		if d.order.deterministic {
+			// Kedua cabang berbagi budget, jadi cabang mana yang terpotong maxNodes harus tetap sama
+			subTreesForParent1 = d.dfsRecursive(parent1Name, depth+1)
+			subTreesForParent2 = d.dfsRecursive(parent2Name, depth+1)
+		} else {
+			var wg sync.WaitGroup
+			wg.Add(2)
 
-		go func() {
-			defer wg.Done()
-			subTreesForParent1 = d.dfsRecursive(parent1Name)
-		}()
+			go func() {
+				defer wg.Done()
+				subTreesForParent1 = d.dfsRecursive(parent1Name, depth+1)
+			}()
 
-		go func() {
-			defer wg.Done()
-			subTreesForParent2 = d.dfsRecursive(parent2Name)
-		}()
+			go func() {
+				defer wg.Done()
+				subTreesForParent2 = d.dfsRecursive(parent2Name, depth+1)
+			}()
 
-		wg.Wait()
-		if !isBasicElement(elemParent1.Name) && len(subTreesForParent1) == 0 {
+			wg.Wait()
+		}
+		if !d.leaves.Has(elemParent1.Name) && len(subTreesForParent1) == 0 {
 			continue
 		}
-		if !isBasicElement(elemParent2.Name) && len(subTreesForParent2) == 0 {
+		if !d.leaves.Has(elemParent2.Name) && len(subTreesForParent2) == 0 {
 			continue
 		}
 
@@ -118,42 +152,52 @@ recipePairLoop:
 	return currTreeCombinations
 }
 
-func dfsMultipleLive(target string, maxRecipes int, delay int, conn *websocket.Conn) ([]TreeNode, int) {
+func dfsMultipleLive(budget *SearchBudget, graph *RecipeGraph, target string, maxRecipes int, leaves LeafSet, order searchOrder, delay int, conn *websocket.Conn) ([]TreeNode, int) {
 	DFSData := DFSData{
-		initialTarget: strings.ToLower(target),
+		budget:        budget,
+		elementMap:    graph.Elements,
+		leaves:        leaves,
+		initialTarget: canonicalName(target),
 		maxRecipes:    maxRecipes,
+		maxDepth:      budget.limits.MaxDepth,
 		cache:         make(map[string][]TreeNode),
-		nodeCounter:   0,
+		order:         order,
 	}
 
-	resultTrees := DFSData.dfsRecursiveLive(strings.ToLower(target), delay, conn)
+	resultTrees := DFSData.dfsRecursiveLive(canonicalName(target), 0, delay, conn)
 
 	if maxRecipes > 0 && len(resultTrees) > maxRecipes {
-		return resultTrees[:maxRecipes], int(DFSData.nodeCounter)
+		return resultTrees[:maxRecipes], budget.Nodes()
 	}
-	return resultTrees, int(DFSData.nodeCounter)
+	return resultTrees, budget.Nodes()
 }
 
-func (d *DFSData) dfsRecursiveLive(currElement string, delay int, conn *websocket.Conn) []TreeNode {
-	d.nodeCounter++
-	currElement = strings.ToLower(currElement)
+func (d *DFSData) dfsRecursiveLive(currElement string, depth int, delay int, conn *websocket.Conn) []TreeNode {
+	budgetLeft := d.budget.Visit()
+	if budgetLeft && d.depthExceeded(depth) {
+		return []TreeNode{}
+	}
+	currElement = canonicalName(currElement)
 
 	if cachedResult, found := d.cache[currElement]; found {
 		return cachedResult
 	}
 
-	elemDetails, exists := elementMap[currElement]
+	elemDetails, exists := d.elementMap[currElement]
 	if !exists {
 		d.cache[currElement] = []TreeNode{}
 		return []TreeNode{}
 	}
 
-	if isBasicElement(elemDetails.Name) {
+	if d.leaves.Has(elemDetails.Name) {
 		leafNode := TreeNode{Name: elemDetails.Name}
 		basicTreeList := []TreeNode{leafNode}
 		d.cache[currElement] = basicTreeList
 		return basicTreeList
 	}
+	if !budgetLeft {
+		return []TreeNode{}
+	}
 
 	if len(elemDetails.Recipes) == 0 {
 		leafNode := TreeNode{Name: elemDetails.Name}
@@ -181,33 +225,36 @@ func (d *DFSData) dfsRecursiveLive(currElement string, delay int, conn *websocke
 	productTier := elemDetails.Tier
 
 recipePairLoop:
-	for _, recipePair := range elemDetails.Recipes {
+	for _, recipePair := range d.order.recipes(currElement, elemDetails.Recipes) {
+		if d.budget.Stopped() {
+			break
+		}
 		if len(recipePair) != 2 {
 			continue
 		}
-		parent1Name := strings.ToLower(recipePair[0])
-		parent2Name := strings.ToLower(recipePair[1])
+		parent1Name := canonicalName(recipePair[0])
+		parent2Name := canonicalName(recipePair[1])
 
-		elemParent1, p1Exists := elementMap[parent1Name]
-		elemParent2, p2Exists := elementMap[parent2Name]
+		elemParent1, p1Exists := d.elementMap[parent1Name]
+		elemParent2, p2Exists := d.elementMap[parent2Name]
 
 		if !p1Exists || !p2Exists {
 			continue
 		}
-		if elemParent1.Tier >= productTier || elemParent2.Tier >= productTier {
+		if (elemParent1.Tier >= productTier && !d.leaves[parent1Name]) || (elemParent2.Tier >= productTier && !d.leaves[parent2Name]) {
 			continue
 		}
 		if strings.Contains(elemParent1.Name, "fanon") || strings.Contains(elemParent2.Name, "fanon") {
 			continue
 		}
 
-		subTreesForParent1 := d.dfsRecursiveLive(parent1Name, delay, conn)
-		if !isBasicElement(elemParent1.Name) && len(subTreesForParent1) == 0 {
+		subTreesForParent1 := d.dfsRecursiveLive(parent1Name, depth+1, delay, conn)
+		if !d.leaves.Has(elemParent1.Name) && len(subTreesForParent1) == 0 {
 			continue
 		}
 
-		subTreesForParent2 := d.dfsRecursiveLive(parent2Name, delay, conn)
-		if !isBasicElement(elemParent2.Name) && len(subTreesForParent2) == 0 {
+		subTreesForParent2 := d.dfsRecursiveLive(parent2Name, depth+1, delay, conn)
+		if !d.leaves.Has(elemParent2.Name) && len(subTreesForParent2) == 0 {
 			continue
 		}
 
@@ -236,10 +283,31 @@ recipePairLoop:
 		"message":      "Finding " + elemDetails.Name + " trees",
 		"duration":     0,
 		"treeData":     currTreeCombinations,
-		"nodesVisited": d.nodeCounter,
+		"nodesVisited": d.budget.Nodes(),
 	})
-	time.Sleep(time.Duration(delay) * time.Millisecond)
+	sleepCtx(d.budget.Context(), time.Duration(delay)*time.Millisecond)
 
 	d.cache[currElement] = currTreeCombinations
 	return currTreeCombinations
 }
+
+type dfsSolver struct{}
+
+func (dfsSolver) Name() string  { return "DFS" }
+func (dfsSolver) Label() string { return "DFS" }
+
+func (dfsSolver) Solve(ctx context.Context, graph *RecipeGraph, target string, opts SolverOptions) SearchResult {
+	budget := newSearchBudget(ctx, opts.Limits)
+	trees, _ := dfsMultiple(budget, graph, target, opts.MaxRecipes, graph.newLeafSet(opts.Inventory), opts.order())
+	return budget.result(trees)
+}
+
+func (dfsSolver) SolveLive(ctx context.Context, graph *RecipeGraph, target string, opts SolverOptions, conn *websocket.Conn) SearchResult {
+	budget := newSearchBudget(ctx, opts.Limits)
+	trees, _ := dfsMultipleLive(budget, graph, target, opts.MaxRecipes, graph.newLeafSet(opts.Inventory), opts.order(), opts.Delay, conn)
+	return budget.result(trees)
+}
+
+func init() {
+	registerSolver(dfsSolver{})
+}
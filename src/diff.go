@@ -0,0 +1,198 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// ElementChange adalah elemen baru beserta resepnya
+type ElementChange struct {
+	Name    string      `json:"name"`
+	Tier    int         `json:"tier"`
+	Recipes [][2]string `json:"recipes"`
+}
+
+// RecipeChange adalah satu resep yang ditambah atau dihapus pada elemen yang ada di kedua dataset
+type RecipeChange struct {
+	Element string    `json:"element"`
+	Recipe  [2]string `json:"recipe"`
+}
+
+// DatasetDiff adalah perbedaan antara dua versi elements.json. Resep elemen yang baru atau dihapus
+// tidak diulang di AddedRecipes/RemovedRecipes.
+type DatasetDiff struct {
+	AddedElements   []ElementChange `json:"addedElements"`
+	RemovedElements []string        `json:"removedElements"`
+	AddedRecipes    []RecipeChange  `json:"addedRecipes"`
+	RemovedRecipes  []RecipeChange  `json:"removedRecipes"`
+	TierChanges     []TierChange    `json:"tierChanges"`
+}
+
+func (d DatasetDiff) Empty() bool {
+	return len(d.AddedElements) == 0 && len(d.RemovedElements) == 0 &&
+		len(d.AddedRecipes) == 0 && len(d.RemovedRecipes) == 0 && len(d.TierChanges) == 0
+}
+
+func readElements(path string) ([]Element, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var elements []Element
+	if err := json.Unmarshal(data, &elements); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return elements, nil
+}
+
+// recipeSet mengembalikan resep sebuah elemen dengan kunci kanonik yang tidak bergantung urutan bahan
+func recipeSet(e Element) (keys []string, recipes map[string][2]string) {
+	recipes = make(map[string][2]string)
+	for _, r := range e.Recipes {
+		if len(r) != 2 {
+			continue
+		}
+		a, b := r[0], r[1]
+		if canonicalName(a) > canonicalName(b) {
+			a, b = b, a
+		}
+		key := canonicalName(a) + "|" + canonicalName(b)
+		if _, ok := recipes[key]; !ok {
+			keys = append(keys, key)
+			recipes[key] = [2]string{a, b}
+		}
+	}
+	sort.Strings(keys)
+	return keys, recipes
+}
+
+// diffDatasets membandingkan dua dataset berdasarkan canonicalName. Semua daftar terurut berdasarkan nama
+// supaya diff dari data yang sama selalu identik.
+func diffDatasets(oldElements, newElements []Element) DatasetDiff {
+	d := DatasetDiff{
+		AddedElements:   []ElementChange{},
+		RemovedElements: []string{},
+		AddedRecipes:    []RecipeChange{},
+		RemovedRecipes:  []RecipeChange{},
+		TierChanges:     []TierChange{},
+	}
+
+	index := func(elements []Element) (map[string]Element, []string) {
+		m := make(map[string]Element, len(elements))
+		var names []string
+		for _, e := range elements {
+			name := canonicalName(e.Name)
+			if _, ok := m[name]; !ok {
+				names = append(names, name)
+			}
+			m[name] = e
+		}
+		sort.Strings(names)
+		return m, names
+	}
+	oldByName, oldNames := index(oldElements)
+	newByName, newNames := index(newElements)
+
+	for _, name := range oldNames {
+		if _, ok := newByName[name]; !ok {
+			d.RemovedElements = append(d.RemovedElements, oldByName[name].Name)
+		}
+	}
+
+	for _, name := range newNames {
+		e := newByName[name]
+		keys, recipes := recipeSet(e)
+		old, ok := oldByName[name]
+		if !ok {
+			added := ElementChange{Name: e.Name, Tier: e.Tier, Recipes: [][2]string{}}
+			for _, k := range keys {
+				added.Recipes = append(added.Recipes, recipes[k])
+			}
+			d.AddedElements = append(d.AddedElements, added)
+			continue
+		}
+
+		oldKeys, oldRecipes := recipeSet(old)
+		for _, k := range keys {
+			if _, ok := oldRecipes[k]; !ok {
+				d.AddedRecipes = append(d.AddedRecipes, RecipeChange{Element: e.Name, Recipe: recipes[k]})
+			}
+		}
+		for _, k := range oldKeys {
+			if _, ok := recipes[k]; !ok {
+				d.RemovedRecipes = append(d.RemovedRecipes, RecipeChange{Element: e.Name, Recipe: oldRecipes[k]})
+			}
+		}
+		if old.Tier != e.Tier {
+			d.TierChanges = append(d.TierChanges, TierChange{Name: e.Name, Old: old.Tier, New: e.Tier})
+		}
+	}
+	return d
+}
+
+// changelog menulis diff sebagai catatan rilis Markdown untuk pemain
+func (d DatasetDiff) changelog(title string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## %s\n\n", title)
+	if d.Empty() {
+		b.WriteString("No changes.\n")
+		return b.String()
+	}
+
+	recipeText := func(r [2]string) string {
+		return r[0] + " + " + r[1]
+	}
+
+	if len(d.AddedElements) > 0 {
+		fmt.Fprintf(&b, "### New elements (%d)\n\n", len(d.AddedElements))
+		for _, e := range d.AddedElements {
+			recipes := make([]string, 0, len(e.Recipes))
+			for _, r := range e.Recipes {
+				recipes = append(recipes, recipeText(r))
+			}
+			if len(recipes) == 0 {
+				recipes = append(recipes, "no recipes")
+			}
+			fmt.Fprintf(&b, "- **%s** (tier %d): %s\n", e.Name, e.Tier, strings.Join(recipes, ", "))
+		}
+		b.WriteString("\n")
+	}
+	if len(d.RemovedElements) > 0 {
+		fmt.Fprintf(&b, "### Removed elements (%d)\n\n", len(d.RemovedElements))
+		for _, name := range d.RemovedElements {
+			fmt.Fprintf(&b, "- %s\n", name)
+		}
+		b.WriteString("\n")
+	}
+	if len(d.AddedRecipes) > 0 {
+		fmt.Fprintf(&b, "### New recipes (%d)\n\n", len(d.AddedRecipes))
+		for _, r := range d.AddedRecipes {
+			fmt.Fprintf(&b, "- %s = %s\n", r.Element, recipeText(r.Recipe))
+		}
+		b.WriteString("\n")
+	}
+	if len(d.RemovedRecipes) > 0 {
+		fmt.Fprintf(&b, "### Removed recipes (%d)\n\n", len(d.RemovedRecipes))
+		for _, r := range d.RemovedRecipes {
+			fmt.Fprintf(&b, "- %s = %s\n", r.Element, recipeText(r.Recipe))
+		}
+		b.WriteString("\n")
+	}
+	if len(d.TierChanges) > 0 {
+		fmt.Fprintf(&b, "### Tier changes (%d)\n\n", len(d.TierChanges))
+		for _, c := range d.TierChanges {
+			fmt.Fprintf(&b, "- %s: %d → %d\n", c.Name, c.Old, c.New)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// summary adalah ringkasan satu baris untuk log
+func (d DatasetDiff) summary() string {
+	return fmt.Sprintf("+%d/-%d elements, +%d/-%d recipes, %d tier changes",
+		len(d.AddedElements), len(d.RemovedElements), len(d.AddedRecipes), len(d.RemovedRecipes), len(d.TierChanges))
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffDatasets(t *testing.T) {
+	old := []Element{
+		{Name: "Air"}, {Name: "Earth"}, {Name: "Fire"}, {Name: "Water"},
+		{Name: "Mud", Tier: 1, Recipes: [][]string{{"Earth", "Water"}}},
+		{Name: "Stone", Tier: 2, Recipes: [][]string{{"Lava", "Air"}, {"Mud", "Fire"}}},
+		{Name: "Lava", Tier: 1, Recipes: [][]string{{"Earth", "Fire"}}},
+		{Name: "Dust", Tier: 1, Recipes: [][]string{{"Air", "Earth"}}},
+	}
+
+	tests := []struct {
+		name      string
+		new       []Element
+		want      DatasetDiff
+		changelog string
+	}{
+		{
+			// Urutan bahan, urutan resep dan huruf besar kecil nama elemen bukan perubahan
+			name: "unchanged",
+			new: []Element{
+				{Name: "air"}, {Name: "Earth"}, {Name: "Fire"}, {Name: "Water"},
+				{Name: "Mud", Tier: 1, Recipes: [][]string{{"Water", "Earth"}}},
+				{Name: "Stone", Tier: 2, Recipes: [][]string{{"Fire", "Mud"}, {"Air", "Lava"}}},
+				{Name: "LAVA", Tier: 1, Recipes: [][]string{{"Fire", "Earth"}}},
+				{Name: "Dust", Tier: 1, Recipes: [][]string{{"Earth", "Air"}}},
+			},
+			want:      DatasetDiff{},
+			changelog: "## Test\n\nNo changes.\n",
+		},
+		{
+			name: "changed",
+			new: []Element{
+				{Name: "Air"}, {Name: "Earth"}, {Name: "Fire"}, {Name: "Water"},
+				{Name: "Mud", Tier: 1, Recipes: [][]string{{"Water", "Earth"}, {"Dust", "Water"}}},
+				{Name: "Stone", Tier: 3, Recipes: [][]string{{"Lava", "Air"}}},
+				{Name: "Lava", Tier: 1, Recipes: [][]string{{"Earth", "Fire"}}},
+				{Name: "Brick", Tier: 2, Recipes: [][]string{{"Mud", "Fire"}}},
+			},
+			want: DatasetDiff{
+				AddedElements:   []ElementChange{{Name: "Brick", Tier: 2, Recipes: [][2]string{{"Fire", "Mud"}}}},
+				RemovedElements: []string{"Dust"},
+				AddedRecipes:    []RecipeChange{{Element: "Mud", Recipe: [2]string{"Dust", "Water"}}},
+				RemovedRecipes:  []RecipeChange{{Element: "Stone", Recipe: [2]string{"Fire", "Mud"}}},
+				TierChanges:     []TierChange{{Name: "Stone", Old: 2, New: 3}},
+			},
+			changelog: "## Test\n\n" +
+				"### New elements (1)\n\n- **Brick** (tier 2): Fire + Mud\n\n" +
+				"### Removed elements (1)\n\n- Dust\n\n" +
+				"### New recipes (1)\n\n- Mud = Dust + Water\n\n" +
+				"### Removed recipes (1)\n\n- Stone = Fire + Mud\n\n" +
+				"### Tier changes (1)\n\n- Stone: 2 → 3\n\n",
+		},
+	}
+	for _, tt := range tests {
+		got := diffDatasets(old, tt.new)
+		if got.Empty() != tt.want.Empty() {
+			t.Errorf("%s: Empty() = %v, want %v", tt.name, got.Empty(), tt.want.Empty())
+		}
+		if !got.Empty() && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s:\ngot  %+v\nwant %+v", tt.name, got, tt.want)
+		}
+		if text := got.changelog("Test"); text != tt.changelog {
+			t.Errorf("%s: changelog\ngot:\n%s\nwant:\n%s", tt.name, text, tt.changelog)
+		}
+	}
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultPageSize    = 50
+	maxPageSize        = 500
+	defaultSuggestions = 10
+)
+
+type ElementSummary struct {
+	Name    string `json:"name"`
+	Tier    int    `json:"tier"`
+	Recipes int    `json:"recipes"`
+	Image   string `json:"image,omitempty"`
+}
+
+type ElementDetail struct {
+	Name        string     `json:"name"`
+	Tier        int        `json:"tier"`
+	Basic       bool       `json:"basic"`
+	Recipes     [][]string `json:"recipes"`
+	UsedIn      []string   `json:"usedIn"`
+	RecipeTrees string     `json:"recipeTrees"`
+	Image       string     `json:"image,omitempty"`
+	Description string     `json:"description,omitempty"`
+	WikiURL     string     `json:"wikiUrl,omitempty"`
+}
+
+func (g *RecipeGraph) summary(name string) ElementSummary {
+	e := g.Elements[name]
+	return ElementSummary{Name: e.Name, Tier: e.Tier, Recipes: len(g.Recipes[name]), Image: imageURL(e.ElementMeta)}
+}
+
+func (g *RecipeGraph) detail(name string) ElementDetail {
+	e := g.Elements[name]
+	d := ElementDetail{
+		Name:    e.Name,
+		Tier:    e.Tier,
+		Basic:   isBasicElement(name),
+		Recipes: [][]string{},
+		UsedIn:  []string{},
+
+		RecipeTrees: g.RecipeCount(name).String(),
+		Image:       imageURL(e.ElementMeta),
+		Description: e.Description,
+		WikiURL:     e.WikiURL,
+	}
+	for _, recipe := range g.Recipes[name] {
+		d.Recipes = append(d.Recipes, []string{g.displayName(recipe[0]), g.displayName(recipe[1])})
+	}
+	for _, product := range g.UsedIn[name] {
+		d.UsedIn = append(d.UsedIn, g.displayName(product))
+	}
+	return d
+}
+
+// displayName mengembalikan nama asli dari dataset, atau nama dengan huruf awal kapital jika tidak ada
+func (g *RecipeGraph) displayName(name string) string {
+	return displayName(g.Elements, name)
+}
+
+// queryInt membaca parameter integer opsional dari query string
+func queryInt(r *http.Request, name string, def int) (int, bool) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return def, true
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return def, false
+	}
+	return n, true
+}
+
+// handleListElements: GET /api/elements?tier=&page=&pageSize=
+func handleListElements(datasets *Datasets, w http.ResponseWriter, r *http.Request) {
+	graph, ok := datasets.requestGraph(w, r.URL.Query().Get("dataset"))
+	if !ok {
+		return
+	}
+
+	page, ok1 := queryInt(r, "page", 1)
+	pageSize, ok2 := queryInt(r, "pageSize", defaultPageSize)
+	tier, ok3 := queryInt(r, "tier", -1)
+	if !ok1 || !ok2 || !ok3 || page < 1 || pageSize < 1 {
+		writeError(w, http.StatusBadRequest, "invalid page, pageSize or tier")
+		return
+	}
+	pageSize = min(pageSize, maxPageSize)
+	filterTier := r.URL.Query().Get("tier") != ""
+
+	matched := make([]ElementSummary, 0)
+	for _, name := range graph.Names {
+		if filterTier && graph.Tiers[name] != tier {
+			continue
+		}
+		matched = append(matched, graph.summary(name))
+	}
+
+	start := min((page-1)*pageSize, len(matched))
+	end := min(start+pageSize, len(matched))
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"elements":       matched[start:end],
+		"total":          len(matched),
+		"page":           page,
+		"pageSize":       pageSize,
+		"datasetVersion": graph.Version,
+	})
+}
+
+// handleGetElement: GET /api/elements/{name}
+func handleGetElement(datasets *Datasets, w http.ResponseWriter, r *http.Request) {
+	graph, ok := datasets.requestGraph(w, r.URL.Query().Get("dataset"))
+	if !ok {
+		return
+	}
+	name, ok := graph.Resolve(r.PathValue("name"))
+	if !ok {
+		writeJSON(w, http.StatusNotFound, map[string]interface{}{
+			"error":       "Unknown element " + strconv.Quote(r.PathValue("name")),
+			"suggestions": graph.suggest(name, 5),
+		})
+		return
+	}
+	writeJSON(w, http.StatusOK, graph.detail(name))
+}
+
+// handleSuggestElements: GET /api/elements/suggest?q=&limit=
+func handleSuggestElements(datasets *Datasets, w http.ResponseWriter, r *http.Request) {
+	graph, ok := datasets.requestGraph(w, r.URL.Query().Get("dataset"))
+	if !ok {
+		return
+	}
+
+	limit, ok := queryInt(r, "limit", defaultSuggestions)
+	if !ok || limit < 1 {
+		writeError(w, http.StatusBadRequest, "invalid limit")
+		return
+	}
+	limit = min(limit, maxPageSize)
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"query":       r.URL.Query().Get("q"),
+		"suggestions": graph.suggest(r.URL.Query().Get("q"), limit),
+	})
+}
+
+type suggestion struct {
+	name  string
+	rank  int // 0 = sama persis, 1 = prefix, 2 = substring, 3 = fuzzy
+	score int // jarak edit untuk fuzzy, panjang nama untuk yang lain
+}
+
+// suggest mencari nama elemen yang cocok dengan query: prefix dulu, lalu substring, lalu fuzzy
+func (g *RecipeGraph) suggest(query string, limit int) []ElementSummary {
+	q := canonicalName(query)
+	if q == "" {
+		return []ElementSummary{}
+	}
+
+	// Toleransi typo: satu kesalahan per empat huruf, minimal satu
+	maxDist := max(1, len([]rune(q))/4)
+
+	var matches []suggestion
+	for _, name := range g.Names {
+		switch {
+		case name == q:
+			matches = append(matches, suggestion{name, 0, 0})
+		case strings.HasPrefix(name, q):
+			matches = append(matches, suggestion{name, 1, len(name)})
+		case strings.Contains(name, q):
+			matches = append(matches, suggestion{name, 2, len(name)})
+		default:
+			// Bandingkan juga dengan prefix nama supaya "steam engin" tetap cocok dengan "steam engine"
+			d := levenshtein(q, name)
+			if runes := []rune(name); len(runes) > len([]rune(q)) {
+				d = min(d, levenshtein(q, string(runes[:len([]rune(q))])))
+			}
+			if d <= maxDist {
+				matches = append(matches, suggestion{name, 3, d})
+			}
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].rank != matches[j].rank {
+			return matches[i].rank < matches[j].rank
+		}
+		if matches[i].score != matches[j].score {
+			return matches[i].score < matches[j].score
+		}
+		return matches[i].name < matches[j].name
+	})
+
+	result := make([]ElementSummary, 0, min(limit, len(matches)))
+	for _, m := range matches {
+		if len(result) >= limit {
+			break
+		}
+		result = append(result, g.summary(m.name))
+	}
+	return result
+}
+
+// levenshtein menghitung jarak edit antara dua string (per rune)
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(min(prev[j]+1, curr[j-1]+1), prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
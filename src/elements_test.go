@@ -0,0 +1,140 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// steamElements menambahkan nama yang mirip satu sama lain ke testElements untuk menguji autocomplete
+var steamElements = append(append([]Element{}, testElements...),
+	Element{Name: "Steam", Recipes: [][]string{{"Water", "Fire"}}},
+	Element{Name: "Boiler", Recipes: [][]string{{"Steam", "Stone"}}},
+	Element{Name: "Steam engine", Recipes: [][]string{{"Steam", "Boiler"}}},
+	Element{Name: "Superheated steam", Recipes: [][]string{{"Steam", "Fire"}}},
+	Element{Name: "Stream", Recipes: [][]string{{"Water", "Earth"}}},
+)
+
+func TestSuggest(t *testing.T) {
+	graph := newTestGraph(t, steamElements)
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		// Sama persis, lalu prefix, lalu substring, lalu fuzzy
+		{"steam", []string{"Steam", "Steam engine", "Superheated steam", "Stream"}},
+		{"steam engin", []string{"Steam engine"}},
+		{"Stem engine", []string{"Steam engine"}},
+		{"staem engine", []string{"Steam engine"}},
+		{"boiller", []string{"Boiler"}},
+		{"xyz", []string{}},
+		{"", []string{}},
+	}
+	for _, tt := range tests {
+		got := []string{}
+		for _, s := range graph.suggest(tt.query, 10) {
+			got = append(got, s.Name)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("suggest(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+
+	if got := graph.suggest("steam", 2); len(got) != 2 || got[1].Name != "Steam engine" {
+		t.Errorf("suggest(steam, 2) = %+v, want Steam and Steam engine", got)
+	}
+}
+
+func TestListElementsAPI(t *testing.T) {
+	datasets := newTestDatasets(t)
+
+	list := func(url string) (int, []ElementSummary, int) {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		handleListElements(datasets, rec, httptest.NewRequest("GET", url, nil))
+		var resp struct {
+			Elements []ElementSummary `json:"elements"`
+			Total    int              `json:"total"`
+		}
+		json.Unmarshal(rec.Body.Bytes(), &resp)
+		return rec.Code, resp.Elements, resp.Total
+	}
+
+	_, all, total := list("/api/elements")
+	if total != len(testElements) || len(all) != total {
+		t.Fatalf("got %d of %d elements, want %d", len(all), total, len(testElements))
+	}
+
+	_, page2, total := list("/api/elements?page=2&pageSize=3")
+	if total != len(testElements) || !reflect.DeepEqual(page2, all[3:6]) {
+		t.Errorf("page 2 = %+v (total %d), want %+v", page2, total, all[3:6])
+	}
+	if _, past, _ := list("/api/elements?page=10&pageSize=3"); len(past) != 0 {
+		t.Errorf("page past the end = %+v, want none", past)
+	}
+
+	_, basics, total := list("/api/elements?tier=0")
+	if total != 4 || len(basics) != 4 {
+		t.Errorf("tier 0: %d elements (total %d), want the 4 basics", len(basics), total)
+	}
+	for _, e := range basics {
+		if e.Tier != 0 {
+			t.Errorf("tier 0 lists %s with tier %d", e.Name, e.Tier)
+		}
+	}
+
+	for _, url := range []string{"/api/elements?page=0", "/api/elements?pageSize=x", "/api/elements?tier=low"} {
+		if code, _, _ := list(url); code != 400 {
+			t.Errorf("%s: status %d, want 400", url, code)
+		}
+	}
+}
+
+func TestGetElementAPI(t *testing.T) {
+	datasets := newTestDatasets(t)
+
+	get := func(name string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/api/elements/"+name, nil)
+		req.SetPathValue("name", name)
+		rec := httptest.NewRecorder()
+		handleGetElement(datasets, rec, req)
+		return rec
+	}
+
+	rec := get("mud")
+	var mud ElementDetail
+	if err := json.Unmarshal(rec.Body.Bytes(), &mud); rec.Code != 200 || err != nil {
+		t.Fatalf("mud: status %d, %v", rec.Code, err)
+	}
+	want := ElementDetail{
+		Name:        "Mud",
+		Tier:        1,
+		Recipes:     [][]string{{"Earth", "Water"}},
+		UsedIn:      []string{"Brick", "Stone"},
+		RecipeTrees: "1",
+	}
+	if !reflect.DeepEqual(mud, want) {
+		t.Errorf("mud = %+v, want %+v", mud, want)
+	}
+
+	var fire ElementDetail
+	json.Unmarshal(get("Fire").Body.Bytes(), &fire)
+	if !fire.Basic || len(fire.Recipes) != 0 {
+		t.Errorf("fire = %+v, want a basic element without recipes", fire)
+	}
+
+	rec = get("mudd")
+	var missing struct {
+		Error       string           `json:"error"`
+		Suggestions []ElementSummary `json:"suggestions"`
+	}
+	json.Unmarshal(rec.Body.Bytes(), &missing)
+	if rec.Code != 404 || missing.Error == "" {
+		t.Fatalf("mudd: status %d, body %s, want 404 with an error", rec.Code, rec.Body)
+	}
+	if len(missing.Suggestions) == 0 || missing.Suggestions[0].Name != "Mud" {
+		t.Errorf("mudd: suggestions %+v, want Mud first", missing.Suggestions)
+	}
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultExploreSteps = 1
+	maxExploreSteps     = 100
+)
+
+// Discovery adalah elemen yang bisa dibuat dari elemen yang sudah dimiliki
+type Discovery struct {
+	Name string `json:"name"`
+	Tier int    `json:"tier"`
+	// Step adalah jumlah langkah minimal dari inventory awal, 1 berarti bisa langsung dibuat
+	Step   int      `json:"step"`
+	Recipe []string `json:"recipe"`
+	// Alternatives adalah banyaknya resep yang sudah bisa dipakai pada langkah tersebut
+	Alternatives int `json:"alternatives"`
+}
+
+type exploreRequest struct {
+	Dataset string   `json:"dataset"`
+	Owned   []string `json:"owned"`
+	Steps   int      `json:"steps"`
+}
+
+// explore mencari semua elemen yang bisa dibuat dalam paling banyak steps langkah, lapis demi lapis
+// seperti forward search pada bidirectional. Tier tidak dipakai di sini: di dalam game pemain
+// boleh menggabungkan elemen apa saja yang sudah dimiliki.
+func (g *RecipeGraph) explore(owned LeafSet, steps int) []Discovery {
+	known := make(map[string]bool, len(owned))
+	frontier := make([]string, 0, len(owned))
+	for name := range owned {
+		if _, ok := g.Elements[name]; ok {
+			known[name] = true
+			frontier = append(frontier, name)
+		}
+	}
+
+	discoveries := make([]Discovery, 0)
+	for step := 1; step <= steps && len(frontier) > 0; step++ {
+		// Elemen baru pasti memakai minimal satu bahan dari lapisan sebelumnya
+		candidates := make(map[string]bool)
+		for _, name := range frontier {
+			for _, product := range g.UsedIn[name] {
+				if !known[product] {
+					candidates[product] = true
+				}
+			}
+		}
+
+		var layer []Discovery
+		for product := range candidates {
+			d := Discovery{Name: g.displayName(product), Tier: g.Tiers[product], Step: step}
+			for _, recipe := range g.Recipes[product] {
+				if !known[recipe[0]] || !known[recipe[1]] {
+					continue
+				}
+				if d.Alternatives == 0 {
+					d.Recipe = []string{g.displayName(recipe[0]), g.displayName(recipe[1])}
+				}
+				d.Alternatives++
+			}
+			if d.Alternatives > 0 {
+				layer = append(layer, d)
+			}
+		}
+
+		// Lapisan baru baru ditandai known setelah semua kandidat diperiksa supaya Step tetap minimal
+		frontier = frontier[:0]
+		for _, d := range layer {
+			name := canonicalName(d.Name)
+			known[name] = true
+			frontier = append(frontier, name)
+		}
+		discoveries = append(discoveries, layer...)
+	}
+
+	sort.Slice(discoveries, func(i, j int) bool {
+		a, b := discoveries[i], discoveries[j]
+		if a.Step != b.Step {
+			return a.Step < b.Step
+		}
+		if a.Tier != b.Tier {
+			return a.Tier < b.Tier
+		}
+		return a.Name < b.Name
+	})
+	return discoveries
+}
+
+// handleExplore: GET/POST /api/explore?owned=mud,fire&steps=N
+func handleExplore(datasets *Datasets, w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	req := exploreRequest{Steps: defaultExploreSteps}
+	if r.Method == http.MethodPost && r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid JSON body: %v", err)
+			return
+		}
+	}
+	if v := r.URL.Query().Get("dataset"); v != "" {
+		req.Dataset = v
+	}
+	if v := r.URL.Query().Get("owned"); v != "" {
+		req.Owned = strings.Split(v, ",")
+	}
+	if v := r.URL.Query().Get("steps"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid steps %q", v)
+			return
+		}
+		req.Steps = n
+	}
+	if req.Steps < 1 {
+		writeError(w, http.StatusBadRequest, "steps must be at least 1")
+		return
+	}
+	req.Steps = min(req.Steps, maxExploreSteps)
+
+	graph, ok := datasets.requestGraph(w, req.Dataset)
+	if !ok {
+		return
+	}
+	owned := graph.newLeafSet(req.Owned)
+
+	ownedNames := make([]string, 0, len(owned))
+	unknown := make([]string, 0)
+	for name := range owned {
+		if _, ok := graph.Elements[name]; ok {
+			ownedNames = append(ownedNames, graph.displayName(name))
+		} else {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(ownedNames)
+	sort.Strings(unknown)
+
+	closure := graph.explore(owned, req.Steps)
+	next := make([]Discovery, 0)
+	for _, d := range closure {
+		if d.Step == 1 {
+			next = append(next, d)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"owned":          ownedNames,
+		"unknown":        unknown,
+		"steps":          req.Steps,
+		"next":           next,
+		"closure":        closure,
+		"datasetVersion": graph.Version,
+	})
+}
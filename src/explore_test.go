@@ -0,0 +1,78 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestExplore(t *testing.T) {
+	graph := newTestGraph(t, testElements)
+
+	// Dari elemen dasar saja: Stone baru bisa dibuat setelah Lava atau Mud ada, Wall setelah Stone dan Brick
+	want := []Discovery{
+		{Name: "Lava", Tier: 1, Step: 1, Recipe: []string{"Earth", "Fire"}, Alternatives: 1},
+		{Name: "Mud", Tier: 1, Step: 1, Recipe: []string{"Earth", "Water"}, Alternatives: 1},
+		// Stone + Mud belum bisa dipakai di langkah 2 karena Stone baru dibuat di langkah yang sama
+		{Name: "Brick", Tier: 2, Step: 2, Recipe: []string{"Mud", "Fire"}, Alternatives: 1},
+		{Name: "Stone", Tier: 2, Step: 2, Recipe: []string{"Lava", "Air"}, Alternatives: 2},
+		{Name: "Wall", Tier: 3, Step: 3, Recipe: []string{"Brick", "Brick"}, Alternatives: 2},
+	}
+	if got := graph.explore(graph.newLeafSet(nil), 10); !reflect.DeepEqual(got, want) {
+		t.Errorf("explore from basics:\n got %+v\nwant %+v", got, want)
+	}
+	if got := graph.explore(graph.newLeafSet(nil), 1); !reflect.DeepEqual(got, want[:2]) {
+		t.Errorf("explore 1 step:\n got %+v\nwant %+v", got, want[:2])
+	}
+
+	// Elemen yang sudah dimiliki tidak muncul lagi, dan tier tidak membatasi kombinasi
+	got := graph.explore(graph.newLeafSet([]string{"Stone"}), 1)
+	names := make([]string, 0, len(got))
+	for _, d := range got {
+		names = append(names, d.Name)
+	}
+	if wantNames := []string{"Lava", "Mud", "Wall"}; !reflect.DeepEqual(names, wantNames) {
+		t.Errorf("explore with Stone owned: %v, want %v", names, wantNames)
+	}
+}
+
+func TestExploreAPI(t *testing.T) {
+	datasets := newTestDatasets(t)
+	rec := httptest.NewRecorder()
+	handleExplore(datasets, rec, httptest.NewRequest("GET", "/api/explore?owned=mud,unobtainium&steps=2", nil))
+	if rec.Code != 200 {
+		t.Fatalf("status %d: %s", rec.Code, rec.Body)
+	}
+
+	var resp struct {
+		Owned   []string    `json:"owned"`
+		Unknown []string    `json:"unknown"`
+		Next    []Discovery `json:"next"`
+		Closure []Discovery `json:"closure"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"Air", "Earth", "Fire", "Mud", "Water"}; !reflect.DeepEqual(resp.Owned, want) {
+		t.Errorf("owned = %v, want %v", resp.Owned, want)
+	}
+	if want := []string{"unobtainium"}; !reflect.DeepEqual(resp.Unknown, want) {
+		t.Errorf("unknown = %v, want %v", resp.Unknown, want)
+	}
+	// Dengan Mud, Brick dan Stone langsung bisa dibuat; Wall baru di langkah kedua
+	for _, d := range resp.Next {
+		if d.Step != 1 {
+			t.Errorf("next contains %s from step %d", d.Name, d.Step)
+		}
+	}
+	if len(resp.Next) != 3 || len(resp.Closure) != 4 {
+		t.Errorf("next %+v, closure %+v, want 3 and 4 discoveries", resp.Next, resp.Closure)
+	}
+
+	rec = httptest.NewRecorder()
+	handleExplore(datasets, rec, httptest.NewRequest("GET", "/api/explore?owned=mud&steps=0", nil))
+	if rec.Code != 400 {
+		t.Errorf("steps=0: status %d, want 400", rec.Code)
+	}
+}
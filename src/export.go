@@ -0,0 +1,271 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-graphviz"
+)
+
+// Format dan bentuk gambar yang didukung /api/export
+const (
+	exportDOT      = "dot"
+	exportSVG      = "svg"
+	exportPNG      = "png"
+	exportMermaid  = "mermaid"
+	exportMarkdown = "markdown"
+
+	layoutTree = "tree" // setiap kemunculan elemen jadi node sendiri, sama seperti TreeNode
+	layoutDAG  = "dag"  // setiap elemen hanya muncul sekali, bahan bersama dipakai ulang
+)
+
+var exportContentTypes = map[string]string{
+	exportDOT:      "text/vnd.graphviz; charset=utf-8",
+	exportSVG:      "image/svg+xml",
+	exportPNG:      "image/png",
+	exportMermaid:  "text/plain; charset=utf-8",
+	exportMarkdown: "text/markdown; charset=utf-8",
+}
+
+const (
+	dotGraphAttrs     = `rankdir=TB; node [shape=box, style="rounded,filled", fillcolor="#ffffff", fontname="Helvetica"]; edge [arrowsize=0.6];`
+	dotBasicAttrs     = `shape=ellipse, fillcolor="#cfe8ff"`
+	dotHighlightAttrs = `fillcolor="#ffe08a", color="#b8860b", penwidth=2.5`
+	dotTargetAttrs    = `peripheries=2, style="rounded,filled,bold"`
+)
+
+// dotNode menulis satu node DOT dengan gaya sesuai jenis elemennya
+func dotNode(buf *bytes.Buffer, id, name string, highlight, target bool) {
+	attrs := []string{"label=" + strconv.Quote(name)}
+	if isBasicElement(canonicalName(name)) {
+		attrs = append(attrs, dotBasicAttrs)
+	}
+	if highlight {
+		attrs = append(attrs, dotHighlightAttrs)
+	}
+	if target {
+		attrs = append(attrs, dotTargetAttrs)
+	}
+	fmt.Fprintf(buf, "  %s [%s];\n", id, strings.Join(attrs, ", "))
+}
+
+// treeDOT membuat DOT dari pohon resep apa adanya, termasuk subpohon yang berulang
+func treeDOT(trees []TreeNode) string {
+	var buf bytes.Buffer
+	buf.WriteString("digraph recipe {\n  " + dotGraphAttrs + "\n")
+
+	next := 0
+	var walk func(node TreeNode, root bool) string
+	walk = func(node TreeNode, root bool) string {
+		id := fmt.Sprintf("n%d", next)
+		next++
+		dotNode(&buf, id, node.Name, node.Highlight, root)
+		for _, child := range node.Children {
+			fmt.Fprintf(&buf, "  %s -> %s;\n", id, walk(child, false))
+		}
+		return id
+	}
+	for _, tree := range trees {
+		walk(tree, true)
+	}
+
+	buf.WriteString("}\n")
+	return buf.String()
+}
+
+// dagDOT membuat DOT dari langkah resep, satu node per elemen
+func (g *RecipeGraph) dagDOT(steps []RecipeStep, targets []string) string {
+	var buf bytes.Buffer
+	buf.WriteString("digraph recipe {\n  " + dotGraphAttrs + "\n")
+
+	ids := make(map[string]string)
+	id := func(name string) string {
+		if v, ok := ids[name]; ok {
+			return v
+		}
+		ids[name] = fmt.Sprintf("n%d", len(ids))
+		dotNode(&buf, ids[name], g.displayName(name), false, contains(targets, name))
+		return ids[name]
+	}
+
+	for _, t := range targets {
+		id(t)
+	}
+	for _, s := range steps {
+		product := id(s.Element)
+		a, b := s.Ingredients[0], s.Ingredients[1]
+		if a == b {
+			fmt.Fprintf(&buf, "  %s -> %s [label=\"×2\"];\n", product, id(a))
+			continue
+		}
+		fmt.Fprintf(&buf, "  %s -> %s;\n", product, id(a))
+		fmt.Fprintf(&buf, "  %s -> %s;\n", product, id(b))
+	}
+
+	buf.WriteString("}\n")
+	return buf.String()
+}
+
+// renderDOT mengubah DOT menjadi SVG atau PNG dengan Graphviz (WebAssembly, tanpa binary eksternal).
+// Renderer PNG bawaan go-graphviz tidak menggambar garis tepi dan panah dengan benar, jadi untuk PNG
+// Graphviz hanya dipakai untuk layout (format "plain") lalu gambarnya dibuat sendiri dengan gg.
+func renderDOT(ctx context.Context, dot string, format string) ([]byte, error) {
+	if format == exportDOT {
+		return []byte(dot), nil
+	}
+	if format == exportPNG {
+		plain, err := renderDOT(ctx, dot, "plain")
+		if err != nil {
+			return nil, err
+		}
+		return drawPlainPNG(plain)
+	}
+
+	gv, err := graphviz.New(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer gv.Close()
+
+	graph, err := graphviz.ParseBytes([]byte(dot))
+	if err != nil {
+		return nil, err
+	}
+	defer graph.Close()
+
+	var buf bytes.Buffer
+	if err := gv.Render(ctx, graph, graphviz.Format(format), &buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// handleExport: GET/POST /api/export?target=...&format=dot|svg|png|mermaid|markdown&layout=tree|dag&index=0
+// Parameter pencarian sama dengan /api/search; dengan targets yang dirender adalah rencana gabungan.
+func handleExport(datasets *Datasets, w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	q := r.URL.Query()
+	format := strings.ToLower(q.Get("format"))
+	if format == "" {
+		format = exportSVG
+	}
+	contentType, ok := exportContentTypes[format]
+	if !ok {
+		writeError(w, http.StatusBadRequest, "unknown format %q, use dot, svg, png, mermaid or markdown", format)
+		return
+	}
+	layout := strings.ToLower(q.Get("layout"))
+	if layout == "" {
+		layout = layoutTree
+	}
+	if layout != layoutTree && layout != layoutDAG {
+		writeError(w, http.StatusBadRequest, "unknown layout %q, use tree or dag", layout)
+		return
+	}
+	index, ok := queryInt(r, "index", 0)
+	if !ok || index < 0 {
+		writeError(w, http.StatusBadRequest, "invalid index")
+		return
+	}
+
+	req, err := parseSearchRequest(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "%v", err)
+		return
+	}
+	graph, ok := datasets.requestGraph(w, req.Dataset)
+	if !ok {
+		return
+	}
+	opts := SolverOptions{
+		MaxRecipes:    max(req.recipeLimit(), index+1),
+		Limits:        req.limits(),
+		Objective:     req.Objective,
+		Inventory:     req.Inventory,
+		Deterministic: req.Deterministic,
+		Seed:          req.Seed,
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), opts.Limits.Timeout)
+	defer cancel()
+
+	// Bentuk tree memakai trees, bentuk dag memakai steps; keduanya berasal dari hasil yang sama
+	var (
+		trees   []TreeNode
+		steps   []RecipeStep
+		targets []string
+	)
+	if len(req.Targets) > 0 {
+		targets, ok = checkPlanTargets(w, graph, req)
+		if !ok {
+			return
+		}
+		plan := planTargets(ctx, graph, targets, opts)
+		if !plan.Found {
+			writeError(w, http.StatusNotFound, "no combined plan found for %v", targets)
+			return
+		}
+		trees, steps = plan.Trees, plan.Steps
+	} else {
+		solver, target, ok := checkSearchRequest(w, graph, req)
+		if !ok {
+			return
+		}
+		result := solver.Solve(ctx, graph, target, opts)
+		if index >= len(result.Trees) {
+			writeError(w, http.StatusNotFound, "found %d recipe plans, no plan at index %d", len(result.Trees), index)
+			return
+		}
+		targets = []string{target}
+		trees = []TreeNode{result.Trees[index]}
+		steps = orderSteps(treeToSteps(trees[0]), targets)
+	}
+
+	startTime := time.Now()
+	var data []byte
+	switch {
+	case format == exportMermaid && layout == layoutDAG:
+		data = []byte(graph.dagMermaid(steps, targets))
+	case format == exportMermaid:
+		data = []byte(treeMermaid(trees))
+	case format == exportMarkdown && layout == layoutDAG:
+		data = []byte(graph.dagMarkdown(steps, targets))
+	case format == exportMarkdown:
+		data = []byte(treeMarkdown(trees))
+	case layout == layoutDAG:
+		data, err = renderDOT(ctx, graph.dagDOT(steps, targets), format)
+	default:
+		data, err = renderDOT(ctx, treeDOT(trees), format)
+	}
+	if errors.Is(err, errPNGTooLarge) {
+		writeError(w, http.StatusUnprocessableEntity, "%v; use format=svg or format=dot for large recipe trees", err)
+		return
+	}
+	if err != nil {
+		log.Printf("Export render error: %v\n", err)
+		writeError(w, http.StatusInternalServerError, "render %s: %v", format, err)
+		return
+	}
+	log.Printf("API export - %s %s, %d bytes in %v\n", layout, format, len(data), time.Since(startTime))
+
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Content-Type", contentType)
+	w.Write(data)
+}
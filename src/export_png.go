@@ -0,0 +1,245 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/fogleman/gg"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+)
+
+const (
+	pngDPI       = 96.0 // satuan layout "plain" adalah inci
+	pngMargin    = 12.0
+	pngFontSize  = 14.0
+	pngArrowSize = 8.0
+
+	// Batas kanvas PNG: satu piksel RGBA 4 byte, jadi 16 juta piksel sekitar 64 MB memori
+	pngMaxSide   = 16384
+	pngMaxPixels = 16 << 20
+)
+
+// errPNGTooLarge dikembalikan jika layout terlalu besar untuk digambar sebagai PNG
+var errPNGTooLarge = errors.New("image too large for png")
+
+type plainNode struct {
+	x, y, w, h float64
+	label      string
+	style      string
+	shape      string
+	color      string
+	fill       string
+}
+
+type plainEdge struct {
+	points       [][2]float64
+	label        string
+	labelX       float64
+	labelY       float64
+	style, color string
+}
+
+// splitPlainLine memecah satu baris output "plain" Graphviz, termasuk string bertanda kutip
+func splitPlainLine(line string) []string {
+	var fields []string
+	var curr strings.Builder
+	inQuote, escaped, started := false, false, false
+	for _, r := range line {
+		switch {
+		case escaped:
+			curr.WriteRune(r)
+			escaped = false
+		case inQuote && r == '\\':
+			escaped = true
+		case r == '"':
+			inQuote = !inQuote
+			started = true
+		case r == ' ' && !inQuote:
+			if started {
+				fields = append(fields, curr.String())
+				curr.Reset()
+				started = false
+			}
+		default:
+			curr.WriteRune(r)
+			started = true
+		}
+	}
+	if started {
+		fields = append(fields, curr.String())
+	}
+	return fields
+}
+
+// parsePlain membaca layout dari format "plain" (lihat dokumentasi Graphviz output formats)
+func parsePlain(plain []byte) (width, height float64, nodes []plainNode, edges []plainEdge, err error) {
+	num := func(s string) float64 {
+		v, e := strconv.ParseFloat(s, 64)
+		if e != nil && err == nil {
+			err = fmt.Errorf("invalid number %q in plain output", s)
+		}
+		return v
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(plain))
+	for scanner.Scan() {
+		f := splitPlainLine(scanner.Text())
+		if len(f) == 0 {
+			continue
+		}
+		switch f[0] {
+		case "graph":
+			if len(f) < 4 {
+				return 0, 0, nil, nil, fmt.Errorf("short graph line in plain output")
+			}
+			width, height = num(f[2]), num(f[3])
+		case "node":
+			if len(f) < 11 {
+				return 0, 0, nil, nil, fmt.Errorf("short node line in plain output")
+			}
+			nodes = append(nodes, plainNode{
+				x: num(f[2]), y: num(f[3]), w: num(f[4]), h: num(f[5]),
+				label: f[6], style: f[7], shape: f[8], color: f[9], fill: f[10],
+			})
+		case "edge":
+			if len(f) < 4 {
+				return 0, 0, nil, nil, fmt.Errorf("short edge line in plain output")
+			}
+			n, _ := strconv.Atoi(f[3])
+			if len(f) < 4+2*n+2 {
+				return 0, 0, nil, nil, fmt.Errorf("short edge line in plain output")
+			}
+			e := plainEdge{}
+			for i := 0; i < n; i++ {
+				e.points = append(e.points, [2]float64{num(f[4+2*i]), num(f[5+2*i])})
+			}
+			rest := f[4+2*n:]
+			if len(rest) >= 5 {
+				e.label, e.labelX, e.labelY = rest[0], num(rest[1]), num(rest[2])
+				rest = rest[3:]
+			}
+			e.style, e.color = rest[0], rest[1]
+			edges = append(edges, e)
+		}
+	}
+	if err == nil {
+		err = scanner.Err()
+	}
+	return width, height, nodes, edges, err
+}
+
+// drawPlainPNG menggambar layout "plain" menjadi PNG
+func drawPlainPNG(plain []byte) ([]byte, error) {
+	width, height, nodes, edges, err := parsePlain(plain)
+	if err != nil {
+		return nil, err
+	}
+
+	// Koordinat plain dimulai dari kiri bawah, sedangkan gambar dari kiri atas
+	px := func(x float64) float64 { return pngMargin + x*pngDPI }
+	py := func(y float64) float64 { return pngMargin + (height-y)*pngDPI }
+
+	// Ukuran dicek sebelum kanvas dialokasikan, pohon yang lebar bisa butuh ratusan MB
+	imgW, imgH := math.Ceil(width*pngDPI+2*pngMargin), math.Ceil(height*pngDPI+2*pngMargin)
+	if imgW > pngMaxSide || imgH > pngMaxSide || imgW*imgH > pngMaxPixels {
+		return nil, fmt.Errorf("%w: %.0fx%.0f pixels, limit is %dx%d and %d pixels", errPNGTooLarge, imgW, imgH, pngMaxSide, pngMaxSide, pngMaxPixels)
+	}
+	dc := gg.NewContext(int(imgW), int(imgH))
+	dc.SetHexColor("#ffffff")
+	dc.Clear()
+
+	f, err := opentype.Parse(goregular.TTF)
+	if err != nil {
+		return nil, err
+	}
+	face, err := opentype.NewFace(f, &opentype.FaceOptions{Size: pngFontSize, DPI: 72})
+	if err != nil {
+		return nil, err
+	}
+	dc.SetFontFace(face)
+
+	for _, e := range edges {
+		if len(e.points) < 2 {
+			continue
+		}
+		dc.SetHexColor(hexColor(e.color))
+		dc.SetLineWidth(1.2)
+		dc.MoveTo(px(e.points[0][0]), py(e.points[0][1]))
+		// Titik spline plain adalah kurva bezier kubik yang bersambung
+		for i := 1; i+2 < len(e.points); i += 3 {
+			dc.CubicTo(px(e.points[i][0]), py(e.points[i][1]),
+				px(e.points[i+1][0]), py(e.points[i+1][1]),
+				px(e.points[i+2][0]), py(e.points[i+2][1]))
+		}
+		dc.Stroke()
+
+		// Spline berhenti di pangkal panah, ujungnya ada di sepanjang arah segmen terakhir
+		last, prev := e.points[len(e.points)-1], e.points[len(e.points)-2]
+		dx, dy := px(last[0])-px(prev[0]), py(last[1])-py(prev[1])
+		if l := math.Hypot(dx, dy); l > 0 {
+			dx, dy = dx/l, dy/l
+			bx, by := px(last[0]), py(last[1])
+			tx, ty := bx+dx*pngArrowSize, by+dy*pngArrowSize
+			dc.MoveTo(tx, ty)
+			dc.LineTo(bx-dy*pngArrowSize/2.5, by+dx*pngArrowSize/2.5)
+			dc.LineTo(bx+dy*pngArrowSize/2.5, by-dx*pngArrowSize/2.5)
+			dc.ClosePath()
+			dc.Fill()
+		}
+
+		if e.label != "" {
+			dc.DrawStringAnchored(e.label, px(e.labelX), py(e.labelY), 0.5, 0.35)
+		}
+	}
+
+	for _, n := range nodes {
+		x, y := px(n.x), py(n.y)
+		w, h := n.w*pngDPI, n.h*pngDPI
+		shape := func() {
+			if n.shape == "ellipse" {
+				dc.DrawEllipse(x, y, w/2, h/2)
+			} else {
+				dc.DrawRoundedRectangle(x-w/2, y-h/2, w, h, 6)
+			}
+		}
+
+		shape()
+		dc.SetHexColor(hexColor(n.fill))
+		dc.Fill()
+
+		shape()
+		dc.SetHexColor(hexColor(n.color))
+		dc.SetLineWidth(1)
+		if strings.Contains(n.style, "bold") || n.color != "black" {
+			dc.SetLineWidth(2.5)
+		}
+		dc.Stroke()
+
+		dc.SetHexColor("#000000")
+		dc.DrawStringAnchored(n.label, x, y, 0.5, 0.35)
+	}
+
+	var buf bytes.Buffer
+	if err := dc.EncodePNG(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// hexColor mengubah warna dari output plain ke format yang dimengerti gg
+func hexColor(c string) string {
+	switch {
+	case strings.HasPrefix(c, "#"):
+		return c
+	case c == "white":
+		return "#ffffff"
+	default:
+		return "#000000"
+	}
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// exportFixture adalah pohon Wall yang membuat Stone dengan dua resep berbeda di dua cabangnya.
+// Layout tree menggambar pohon apa adanya; layout dag memakai langkah dari orderSteps, satu resep per elemen.
+func exportFixture(t *testing.T) (*RecipeGraph, []TreeNode, []RecipeStep, []string) {
+	t.Helper()
+	graph := newTestGraph(t, testElements)
+	leaf := func(name string) TreeNode { return TreeNode{Name: name} }
+	node := func(name string, a, b TreeNode) TreeNode { return TreeNode{Name: name, Children: []TreeNode{a, b}} }
+
+	tree := node("Wall",
+		node("Stone", node("Lava", leaf("Earth"), leaf("Fire")), leaf("Air")),
+		node("Stone", node("Mud", leaf("Earth"), leaf("Water")), leaf("Fire")),
+	)
+	targets := []string{"wall"}
+	return graph, []TreeNode{tree}, orderSteps(treeToSteps(tree), targets), targets
+}
+
+// exportGraphCounts menghitung node, label node dan edge dari keluaran DOT atau Mermaid
+func exportGraphCounts(out string, nodeRe, edgeRe *regexp.Regexp) (nodes int, labels map[string]int, edges int) {
+	labels = make(map[string]int)
+	for _, m := range nodeRe.FindAllStringSubmatch(out, -1) {
+		nodes++
+		labels[m[1]]++
+	}
+	return nodes, labels, len(edgeRe.FindAllString(out, -1))
+}
+
+func TestExportGraphs(t *testing.T) {
+	graph, trees, steps, targets := exportFixture(t)
+
+	dotNodeRe := regexp.MustCompile(`(?m)^  n\d+ \[label="([^"]+)"`)
+	dotEdgeRe := regexp.MustCompile(`(?m)^  n\d+ -> n\d+`)
+	mermaidNodeRe := regexp.MustCompile(`(?m)^  n\d+\(?\["([^"]+)"\]\)?`)
+	mermaidEdgeRe := regexp.MustCompile(`(?m)^  n\d+ -->`)
+
+	tests := []struct {
+		name           string
+		out            string
+		nodeRe, edgeRe *regexp.Regexp
+		wantNodes      int
+		wantEdges      int
+	}{
+		// Setiap kemunculan jadi node sendiri: 11 node, 10 edge
+		{"dot tree", treeDOT(trees), dotNodeRe, dotEdgeRe, 11, 10},
+		{"mermaid tree", treeMermaid(trees), mermaidNodeRe, mermaidEdgeRe, 11, 10},
+		// Wall, Stone, Lava, Air, Earth, Fire; Stone + Stone digambar sebagai satu edge ×2
+		{"dot dag", graph.dagDOT(steps, targets), dotNodeRe, dotEdgeRe, 6, 5},
+		{"mermaid dag", graph.dagMermaid(steps, targets), mermaidNodeRe, mermaidEdgeRe, 6, 5},
+	}
+	for _, tt := range tests {
+		nodes, labels, edges := exportGraphCounts(tt.out, tt.nodeRe, tt.edgeRe)
+		if nodes != tt.wantNodes || edges != tt.wantEdges {
+			t.Errorf("%s: %d nodes and %d edges, want %d and %d\n%s", tt.name, nodes, edges, tt.wantNodes, tt.wantEdges, tt.out)
+		}
+		if strings.HasSuffix(tt.name, "dag") {
+			for label, n := range labels {
+				if n > 1 {
+					t.Errorf("%s: %s drawn %d times, want once", tt.name, label, n)
+				}
+			}
+			if labels["Mud"] != 0 {
+				t.Errorf("%s: Mud is only needed by the discarded Stone recipe", tt.name)
+			}
+		}
+	}
+}
+
+func TestExportMarkdown(t *testing.T) {
+	graph, trees, steps, targets := exportFixture(t)
+
+	wantTree := `- **Wall** = Stone + Stone
+  - Stone = Lava + Air
+    - Lava = Earth + Fire
+      - Earth
+      - Fire
+    - Air
+  - Stone = Mud + Fire
+    - Mud = Earth + Water
+      - Earth
+      - Water
+    - Fire
+`
+	if got := treeMarkdown(trees); got != wantTree {
+		t.Errorf("tree markdown:\n%s\nwant:\n%s", got, wantTree)
+	}
+
+	wantDAG := `**Wall**
+
+1. Earth + Fire → Lava
+2. Lava + Air → Stone
+3. Stone + Stone → Wall
+`
+	if got := graph.dagMarkdown(steps, targets); got != wantDAG {
+		t.Errorf("dag markdown:\n%s\nwant:\n%s", got, wantDAG)
+	}
+}
+
+func TestExportPNG(t *testing.T) {
+	graph, trees, steps, targets := exportFixture(t)
+	ctx := context.Background()
+
+	for name, dot := range map[string]string{
+		"tree": treeDOT(trees),
+		"dag":  graph.dagDOT(steps, targets),
+	} {
+		data, err := renderDOT(ctx, dot, exportPNG)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		img, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("%s: not a PNG: %v", name, err)
+		}
+		if b := img.Bounds(); b.Dx() < 50 || b.Dy() < 50 {
+			t.Errorf("%s: image is only %dx%d", name, b.Dx(), b.Dy())
+		}
+	}
+}
+
+// TestExportPNGTooLarge: pohon biner dengan 512 daun jauh lebih lebar dari pngMaxSide, jadi PNG-nya
+// ditolak sebelum kanvas dialokasikan, sedangkan SVG tetap bisa dibuat
+func TestExportPNGTooLarge(t *testing.T) {
+	var build func(depth int, name string) TreeNode
+	build = func(depth int, name string) TreeNode {
+		if depth == 0 {
+			return TreeNode{Name: name}
+		}
+		return TreeNode{Name: name, Children: []TreeNode{build(depth-1, name+"a"), build(depth-1, name+"b")}}
+	}
+	dot := treeDOT([]TreeNode{build(9, "X")})
+
+	_, err := renderDOT(context.Background(), dot, exportPNG)
+	if !errors.Is(err, errPNGTooLarge) {
+		t.Errorf("png of 512 leaves: err = %v, want errPNGTooLarge", err)
+	}
+	if _, err := renderDOT(context.Background(), dot, exportSVG); err != nil {
+		t.Errorf("svg of 512 leaves: %v", err)
+	}
+}
+
+func TestExportAPIPNGTooLarge(t *testing.T) {
+	// Double k dibuat dari dua Double k-1, jadi pohonnya punya 2^(k+1) daun
+	elements := append([]Element{}, testElements...)
+	elements = append(elements, Element{Name: "Double 0", Recipes: [][]string{{"Air", "Water"}}})
+	for k := 1; k < 9; k++ {
+		prev := fmt.Sprintf("Double %d", k-1)
+		elements = append(elements, Element{Name: fmt.Sprintf("Double %d", k), Recipes: [][]string{{prev, prev}}})
+	}
+	path := filepath.Join(t.TempDir(), "elements.json")
+	writeTestDataset(t, path, elements)
+	datasets, err := newDatasets(gameLA2, map[string]string{gameLA2: path}, true, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec := httptest.NewRecorder()
+	handleExport(datasets, rec, httptest.NewRequest("GET", "/api/export?target=double%208&algorithm=DFS&format=png", nil))
+	if rec.Code != http.StatusUnprocessableEntity || !strings.Contains(rec.Body.String(), "format=svg") {
+		t.Errorf("png: status %d, body %s, want 422 pointing to svg", rec.Code, rec.Body)
+	}
+
+	rec = httptest.NewRecorder()
+	handleExport(datasets, rec, httptest.NewRequest("GET", "/api/export?target=double%208&algorithm=DFS&format=svg", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("svg: status %d, body %s", rec.Code, rec.Body)
+	}
+}
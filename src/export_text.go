@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+const mermaidClassDefs = `  classDef basic fill:#cfe8ff,stroke:#5b8db8
+  classDef highlight fill:#ffe08a,stroke:#b8860b,stroke-width:3px
+  classDef target stroke-width:3px
+`
+
+// mermaidNode menulis satu node Mermaid; elemen dasar berbentuk stadium seperti ellipse di DOT
+func mermaidNode(b *strings.Builder, id, name string, highlight, target bool) {
+	label := strings.ReplaceAll(name, `"`, "#quot;")
+	if isBasicElement(canonicalName(name)) {
+		fmt.Fprintf(b, "  %s([\"%s\"]):::basic\n", id, label)
+	} else {
+		fmt.Fprintf(b, "  %s[\"%s\"]\n", id, label)
+	}
+	if highlight {
+		fmt.Fprintf(b, "  class %s highlight\n", id)
+	}
+	if target {
+		fmt.Fprintf(b, "  class %s target\n", id)
+	}
+}
+
+// treeMermaid membuat blok Mermaid dari pohon resep apa adanya, termasuk subpohon yang berulang
+func treeMermaid(trees []TreeNode) string {
+	var b strings.Builder
+	b.WriteString("```mermaid\ngraph TD\n")
+
+	next := 0
+	var walk func(node TreeNode, root bool) string
+	walk = func(node TreeNode, root bool) string {
+		id := fmt.Sprintf("n%d", next)
+		next++
+		mermaidNode(&b, id, node.Name, node.Highlight, root)
+		for _, child := range node.Children {
+			fmt.Fprintf(&b, "  %s --> %s\n", id, walk(child, false))
+		}
+		return id
+	}
+	for _, tree := range trees {
+		walk(tree, true)
+	}
+
+	b.WriteString(mermaidClassDefs + "```\n")
+	return b.String()
+}
+
+// dagMermaid membuat blok Mermaid dari langkah resep, satu node per elemen
+func (g *RecipeGraph) dagMermaid(steps []RecipeStep, targets []string) string {
+	var b strings.Builder
+	b.WriteString("```mermaid\ngraph TD\n")
+
+	ids := make(map[string]string)
+	id := func(name string) string {
+		if v, ok := ids[name]; ok {
+			return v
+		}
+		ids[name] = fmt.Sprintf("n%d", len(ids))
+		mermaidNode(&b, ids[name], g.displayName(name), false, contains(targets, name))
+		return ids[name]
+	}
+
+	for _, t := range targets {
+		id(t)
+	}
+	for _, s := range steps {
+		product := id(s.Element)
+		a, c := s.Ingredients[0], s.Ingredients[1]
+		if a == c {
+			fmt.Fprintf(&b, "  %s -->|×2| %s\n", product, id(a))
+			continue
+		}
+		fmt.Fprintf(&b, "  %s --> %s\n", product, id(a))
+		fmt.Fprintf(&b, "  %s --> %s\n", product, id(c))
+	}
+
+	b.WriteString(mermaidClassDefs + "```\n")
+	return b.String()
+}
+
+// treeMarkdown membuat daftar bersarang, misalnya "- **Brick** = Fire + Mud"
+func treeMarkdown(trees []TreeNode) string {
+	var b strings.Builder
+	var walk func(node TreeNode, depth int)
+	walk = func(node TreeNode, depth int) {
+		name := node.Name
+		if depth == 0 || node.Highlight {
+			name = "**" + name + "**"
+		}
+		b.WriteString(strings.Repeat("  ", depth) + "- " + name)
+		if len(node.Children) == 2 {
+			fmt.Fprintf(&b, " = %s + %s", node.Children[0].Name, node.Children[1].Name)
+		}
+		b.WriteString("\n")
+		for _, child := range node.Children {
+			walk(child, depth+1)
+		}
+	}
+	for _, tree := range trees {
+		walk(tree, 0)
+	}
+	return b.String()
+}
+
+// dagMarkdown membuat daftar langkah bernomor; setiap bahan hanya dibuat sekali
+func (g *RecipeGraph) dagMarkdown(steps []RecipeStep, targets []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "**%s**\n\n", strings.Join(g.displayNames(targets), ", "))
+	if len(steps) == 0 {
+		b.WriteString("Nothing to craft.\n")
+	}
+	for _, s := range g.craftSequence(steps) {
+		b.WriteString(s.Text + "\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var errNotCached = errors.New("page not in cache (offline mode)")
+
+// PageFetcher mengambil halaman wiki dan menyimpan HTML mentahnya di cacheDir.
+// Halaman yang sudah ada di cache tidak diunduh lagi, jadi scraping yang terhenti
+// bisa dilanjutkan dan parser bisa dijalankan ulang tanpa jaringan.
+type PageFetcher struct {
+	CacheDir string
+	// Offline hanya membaca cache dan tidak pernah membuka koneksi
+	Offline bool
+	// Retries adalah jumlah percobaan ulang untuk 429, 5xx dan error jaringan
+	Retries int
+	// Backoff adalah jeda sebelum percobaan ulang pertama, lalu dikali dua setiap kali gagal
+	Backoff time.Duration
+	// Delay adalah jeda setelah setiap unduhan supaya tidak membebani server wiki
+	Delay time.Duration
+
+	client *http.Client
+}
+
+func newPageFetcher(cacheDir string, offline bool) *PageFetcher {
+	return &PageFetcher{
+		CacheDir: cacheDir,
+		Offline:  offline,
+		Retries:  5,
+		Backoff:  time.Second,
+		Delay:    300 * time.Millisecond,
+		client:   &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+
+// cachePath membuat nama file dari URL: bagian yang mudah dibaca ditambah hash supaya tetap unik
+func (f *PageFetcher) cachePath(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	slug := url
+	if i := strings.LastIndex(slug, "/"); i >= 0 {
+		slug = slug[i+1:]
+	}
+	slug = unsafeFileChars.ReplaceAllString(slug, "_")
+	if len(slug) > 60 {
+		slug = slug[:60]
+	}
+	return filepath.Join(f.CacheDir, slug+"-"+hex.EncodeToString(sum[:])[:12]+".html")
+}
+
+// Cached mengembalikan true jika halaman sudah ada di cache
+func (f *PageFetcher) Cached(url string) bool {
+	_, err := os.Stat(f.cachePath(url))
+	return err == nil
+}
+
+// Fetch mengembalikan HTML halaman, dari cache jika ada
+func (f *PageFetcher) Fetch(url string) ([]byte, error) {
+	path := f.cachePath(url)
+	if data, err := os.ReadFile(path); err == nil {
+		return data, nil
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	if f.Offline {
+		return nil, fmt.Errorf("%s: %w", url, errNotCached)
+	}
+
+	data, err := f.download(url)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(f.CacheDir, 0755); err != nil {
+		return nil, err
+	}
+	// Tulis ke file sementara dulu supaya halaman yang terpotong tidak pernah masuk cache
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// download mengunduh url dengan retry dan exponential backoff untuk 429, 5xx dan error jaringan
+func (f *PageFetcher) download(url string) ([]byte, error) {
+	defer time.Sleep(f.Delay)
+
+	backoff := f.Backoff
+	var lastErr error
+	for attempt := 0; attempt <= f.Retries; attempt++ {
+		if attempt > 0 {
+			log.Printf("Retrying %s in %v (attempt %d/%d): %v\n", url, backoff, attempt, f.Retries, lastErr)
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+
+		req, err := http.NewRequest("GET", url, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("User-Agent", "Mozilla/5.0")
+		res, err := f.client.Do(req)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		data, err := io.ReadAll(res.Body)
+		res.Body.Close()
+
+		switch {
+		case res.StatusCode == http.StatusTooManyRequests || res.StatusCode >= 500:
+			lastErr = fmt.Errorf("status code: %d", res.StatusCode)
+			// Ikuti Retry-After dari server jika lebih lama dari backoff
+			if s, convErr := strconv.Atoi(res.Header.Get("Retry-After")); convErr == nil && time.Duration(s)*time.Second > backoff {
+				backoff = time.Duration(s) * time.Second
+			}
+		case res.StatusCode != http.StatusOK:
+			return nil, fmt.Errorf("%s: status code: %d", url, res.StatusCode)
+		case err != nil:
+			lastErr = err
+		default:
+			return data, nil
+		}
+	}
+	return nil, fmt.Errorf("%s: giving up after %d retries: %w", url, f.Retries, lastErr)
+}
+
+// FetchAsset mengunduh file seperti gambar elemen ke path. File yang sudah ada tidak diunduh lagi,
+// jadi folder assets berfungsi sebagai cache sendiri.
+func (f *PageFetcher) FetchAsset(url, path string) error {
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+	if f.Offline {
+		return fmt.Errorf("%s: %w", url, errNotCached)
+	}
+
+	data, err := f.download(url)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
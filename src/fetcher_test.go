@@ -0,0 +1,90 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testFetcher memakai cache sementara dan jeda sesingkat mungkin supaya retry tidak memperlambat test
+func testFetcher(t *testing.T, offline bool) *PageFetcher {
+	t.Helper()
+	f := newPageFetcher(t.TempDir(), offline)
+	f.Backoff = time.Millisecond
+	f.Delay = 0
+	return f
+}
+
+func TestFetchRetriesAndCaches(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/missing":
+			http.NotFound(w, r)
+		case r.URL.Path == "/down":
+			requests.Add(1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case requests.Add(1) == 1:
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			w.Write([]byte("<html>Mud</html>"))
+		}
+	}))
+	defer srv.Close()
+
+	f := testFetcher(t, false)
+	url := srv.URL + "/wiki/Mud"
+	data, err := f.Fetch(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "<html>Mud</html>" {
+		t.Errorf("body = %q", data)
+	}
+	if n := requests.Load(); n != 2 {
+		t.Errorf("%d requests, want 429 then 200", n)
+	}
+
+	// Pengambilan kedua dibaca dari cache tanpa request baru
+	if !f.Cached(url) {
+		t.Errorf("%s not cached", url)
+	}
+	if _, err := f.Fetch(url); err != nil {
+		t.Fatal(err)
+	}
+	if n := requests.Load(); n != 2 {
+		t.Errorf("second fetch made a request, %d in total", n)
+	}
+
+	// -offline memakai cache yang sama dan tidak pernah membuka koneksi
+	offline := testFetcher(t, true)
+	offline.CacheDir = f.CacheDir
+	if data, err := offline.Fetch(url); err != nil || string(data) != "<html>Mud</html>" {
+		t.Errorf("offline fetch of a cached page: %q, %v", data, err)
+	}
+	if _, err := offline.Fetch(srv.URL + "/wiki/Lava"); !errors.Is(err, errNotCached) {
+		t.Errorf("offline fetch of an uncached page: %v, want errNotCached", err)
+	}
+	if n := requests.Load(); n != 2 {
+		t.Errorf("offline fetch made a request, %d in total", n)
+	}
+
+	// 404 tidak diulang; 5xx diulang sampai Retries habis
+	if _, err := f.Fetch(srv.URL + "/missing"); err == nil {
+		t.Error("fetching a 404 page succeeded")
+	}
+	requests.Store(0)
+	f.Retries = 2
+	if _, err := f.Fetch(srv.URL + "/down"); err == nil {
+		t.Error("fetching a page that always returns 503 succeeded")
+	}
+	if n := requests.Load(); n != 3 {
+		t.Errorf("%d requests for a 503 page with 2 retries, want 3", n)
+	}
+	if f.Cached(srv.URL + "/down") {
+		t.Error("failed page was cached")
+	}
+}
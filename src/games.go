@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Nama game/pack, dipakai sebagai nama dataset di server dan di flag -game
+const (
+	gameLA2 = "la2"
+	gameLA1 = "la1"
+	gameMM  = "mm"
+)
+
+// Game menentukan halaman dan bagian wiki yang membentuk satu dataset
+type Game struct {
+	Name  string
+	Title string
+	// ListPages adalah halaman daftar elemen, digabung jika lebih dari satu
+	ListPages []string
+	// Sections adalah game yang bagian resepnya diambil dari halaman elemen
+	Sections []string
+	// OutFile adalah nama output default untuk perintah scrape
+	OutFile string
+}
+
+// Myths and Monsters adalah pack tambahan Little Alchemy 2, jadi datasetnya berisi semua elemen
+// Little Alchemy 2 ditambah elemen pack tersebut supaya resepnya bisa diturunkan dari elemen dasar.
+var scrapeGames = []Game{
+	{
+		Name:      gameLA2,
+		Title:     "Little Alchemy 2",
+		ListPages: []string{"/wiki/Elements_(Little_Alchemy_2)"},
+		Sections:  []string{gameLA2},
+		OutFile:   "elements.json",
+	},
+	{
+		Name:      gameLA1,
+		Title:     "Little Alchemy",
+		ListPages: []string{"/wiki/Elements_(Little_Alchemy)"},
+		Sections:  []string{gameLA1},
+		OutFile:   "elements_la1.json",
+	},
+	{
+		Name:      gameMM,
+		Title:     "Little Alchemy 2: Myths and Monsters",
+		ListPages: []string{"/wiki/Elements_(Little_Alchemy_2)", "/wiki/Myths_and_Monsters"},
+		Sections:  []string{gameLA2, gameMM},
+		OutFile:   "elements_mm.json",
+	},
+}
+
+// servedPath adalah file dataset game ini yang dipakai server: ELEMENTS_PATH (atau data/elements.json)
+// untuk la2, entri DATASETS jika ada, selain itu OutFile di folder yang sama dengan dataset default
+func (g Game) servedPath() string {
+	if g.Name == gameLA2 {
+		return defaultDataPath()
+	}
+	if paths, err := parseDatasetSpecs(os.Getenv("DATASETS")); err == nil && paths[g.Name] != "" {
+		return paths[g.Name]
+	}
+	return filepath.Join(filepath.Dir(defaultDataPath()), g.OutFile)
+}
+
+func lookupGame(name string) (Game, error) {
+	names := make([]string, 0, len(scrapeGames))
+	for _, g := range scrapeGames {
+		if strings.EqualFold(g.Name, name) {
+			return g, nil
+		}
+		names = append(names, g.Name)
+	}
+	return Game{}, fmt.Errorf("unknown game %q, use %s", name, strings.Join(names, ", "))
+}
+
+// headerGame menebak game dari teks judul bagian (lowercase), "" jika judul bukan nama game
+func headerGame(text string) string {
+	switch {
+	case strings.Contains(text, "myths and monsters"):
+		return gameMM
+	case strings.Contains(text, "little alchemy 2"):
+		return gameLA2
+	case strings.Contains(text, "little alchemy"):
+		return gameLA1
+	}
+	return ""
+}
+
+// covers mengembalikan true jika resep di bagian game tersebut termasuk dataset ini
+func (g Game) covers(game string) bool {
+	return game != "" && contains(g.Sections, game)
+}
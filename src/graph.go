@@ -0,0 +1,148 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// RecipeGraph adalah representasi elements.json yang dibangun sekali saat startup.
+// Setelah dibuat, graph tidak boleh diubah sehingga aman dipakai banyak pencarian sekaligus.
+type RecipeGraph struct {
+	// Elements diindeks dengan canonicalName; Element.Name tetap nama asli untuk ditampilkan
+	Elements map[string]Element
+	// Recipes berisi pasangan bahan (kunci kanonik) untuk setiap elemen
+	Recipes map[string][][2]string
+	// UsedIn berisi daftar elemen yang bisa dibuat dari suatu bahan
+	UsedIn map[string][]string
+	// Tiers berisi tier setiap elemen
+	Tiers map[string]int
+	// Names berisi semua kunci kanonik, terurut
+	Names []string
+	// Counts berisi jumlah pohon resep berbeda untuk setiap elemen
+	Counts map[string]*big.Int
+	// Resolver mengubah nama dari request (alias, bentuk jamak, akhiran wiki) menjadi kunci di atas
+	Resolver *NameResolver
+
+	// Version adalah hash isi file dataset, dikirim bersama hasil pencarian
+	Version  string
+	LoadedAt time.Time
+	// Validation adalah hasil validateElements saat file dimuat
+	Validation ValidationReport
+}
+
+func loadRecipeGraph(path string) (*RecipeGraph, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+
+	var elements []Element
+	if err := json.Unmarshal(data, &elements); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+
+	aliasData, err := readAliasFile(filepath.Dir(path))
+	if err != nil {
+		return nil, err
+	}
+	aliases, err := parseAliases(filepath.Dir(path), aliasData)
+	if err != nil {
+		return nil, err
+	}
+	elements = normalizeElements(elements, aliases)
+
+	g := newRecipeGraph(elements, aliases)
+	g.Validation = validateElements(elements)
+	if err := g.check(); err != nil {
+		return nil, fmt.Errorf("invalid dataset %s: %w", path, err)
+	}
+	g.Version = datasetVersion(data, aliasData)
+	g.LoadedAt = time.Now()
+	return g, nil
+}
+
+// datasetVersion adalah hash elements.json dan aliases.json, karena alias ikut menentukan hasil Resolve.
+// Tanpa aliases.json hasilnya sama dengan hash elements.json saja, jadi versi lama tidak berubah.
+func datasetVersion(elements, aliases []byte) string {
+	h := sha256.New()
+	h.Write(elements)
+	if aliases != nil {
+		h.Write([]byte{0})
+		h.Write(aliases)
+	}
+	return hex.EncodeToString(h.Sum(nil))[:12]
+}
+
+// newRecipeGraph mengharapkan elements yang sudah melewati normalizeElements
+func newRecipeGraph(elements []Element, aliases map[string]string) *RecipeGraph {
+	g := &RecipeGraph{
+		Elements: make(map[string]Element, len(elements)),
+		Recipes:  make(map[string][][2]string, len(elements)),
+		UsedIn:   make(map[string][]string),
+		Tiers:    make(map[string]int, len(elements)),
+		Resolver: newNameResolver(elements, aliases),
+	}
+
+	for _, e := range elements {
+		name := canonicalName(e.Name)
+		g.Elements[name] = e
+		g.Tiers[name] = e.Tier
+	}
+
+	for name, e := range g.Elements {
+		usedBy := make(map[string]bool)
+		for _, recipe := range e.Recipes {
+			if len(recipe) != 2 {
+				continue
+			}
+			a := canonicalName(recipe[0])
+			b := canonicalName(recipe[1])
+			g.Recipes[name] = append(g.Recipes[name], [2]string{a, b})
+			for _, ing := range []string{a, b} {
+				if !usedBy[ing] {
+					usedBy[ing] = true
+					g.UsedIn[ing] = append(g.UsedIn[ing], name)
+				}
+			}
+		}
+		g.Names = append(g.Names, name)
+	}
+
+	sort.Strings(g.Names)
+	for ing := range g.UsedIn {
+		sort.Strings(g.UsedIn[ing])
+	}
+	g.Counts = countRecipeTrees(g)
+	return g
+}
+
+// check memastikan dataset cukup lengkap untuk dipakai solver
+func (g *RecipeGraph) check() error {
+	if len(g.Elements) == 0 {
+		return fmt.Errorf("no elements")
+	}
+	for _, b := range basicElements {
+		if _, ok := g.Elements[b]; !ok {
+			return fmt.Errorf("basic element %q missing", b)
+		}
+	}
+	return nil
+}
+
+// Resolve mengembalikan kunci elemen untuk nama dari request, lihat NameResolver.Resolve
+func (g *RecipeGraph) Resolve(name string) (string, bool) {
+	return g.Resolver.Resolve(name)
+}
+
+func (g *RecipeGraph) Element(name string) (Element, bool) {
+	key, _ := g.Resolve(name)
+	e, ok := g.Elements[key]
+	return e, ok
+}
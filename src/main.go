@@ -1,12 +1,18 @@
 package main
 
 import (
+	"context"
+	"crypto/subtle"
 	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
-	"io/ioutil"
 	"log"
+	"net"
 	"net/http"
 	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -17,31 +23,85 @@ var upgrader = websocket.Upgrader{
 	CheckOrigin: func(r *http.Request) bool { return true },
 }
 
-var elementMap map[string]Element
-
 type RequestData struct {
-	Algorithm  string `json:"algorithm"`
-	Target     string `json:"target"`
-	MaxRecipes int    `json:"maxRecipes"`
-	LiveUpdate bool   `json:"liveUpdate"`
-	Delay      int    `json:"delay"`
+	// Dataset memilih dataset game (la2, la1, mm); kosong berarti dataset default server
+	Dataset   string `json:"dataset"`
+	Algorithm string `json:"algorithm"`
+	Target    string `json:"target"`
+	// Targets dipakai untuk satu rencana gabungan beberapa elemen sekaligus
+	Targets    []string `json:"targets"`
+	MaxRecipes int      `json:"maxRecipes"`
+	LiveUpdate bool     `json:"liveUpdate"`
+	Delay      int      `json:"delay"`
+	Objective  string   `json:"objective"`
+	// Inventory adalah elemen yang sudah ditemukan pemain, dipakai sebagai daun pohon resep
+	Inventory []string `json:"inventory"`
+	// Sequence menambahkan urutan langkah linear untuk setiap pohon di response
+	Sequence bool `json:"sequence"`
+	// Metadata menambahkan gambar, deskripsi dan link wiki ke setiap node pohon
+	Metadata bool `json:"metadata"`
+	// Deterministic membuat hasil yang sama untuk request yang sama (default untuk REST API, tidak untuk /ws)
+	Deterministic bool `json:"deterministic"`
+	// Seed selain 0 mengacak urutan eksplorasi secara reproducible, lihat SolverOptions.Seed
+	Seed int64 `json:"seed"`
+
+	// Batas pencarian opsional, dibatasi oleh serverLimits
+	TimeoutMs int `json:"timeoutMs"`
+	MaxNodes  int `json:"maxNodes"`
+	MaxDepth  int `json:"maxDepth"`
+	MaxQueue  int `json:"maxQueue"`
+}
+
+// recipeLimit mengembalikan MaxRecipes; 0 (misalnya field tidak dikirim) berarti satu resep,
+// supaya tidak ada solver yang mencari tanpa target jumlah
+func (r RequestData) recipeLimit() int {
+	return max(r.MaxRecipes, 1)
+}
+
+func (r RequestData) limits() SearchLimits {
+	return clampLimits(SearchLimits{
+		Timeout:  time.Duration(r.TimeoutMs) * time.Millisecond,
+		MaxNodes: r.MaxNodes,
+		MaxDepth: r.MaxDepth,
+		MaxQueue: r.MaxQueue,
+	})
 }
 
 type Element struct {
 	Name    string     `json:"name"`
 	Recipes [][]string `json:"recipes"`
 	Tier    int        `json:"tier"`
+	ElementMeta
+}
+
+// ElementMeta adalah informasi tambahan dari halaman wiki elemen; kosong pada dataset lama
+type ElementMeta struct {
+	// Image adalah URL gambar di wiki, ImageFile salinannya di folder assets (relatif terhadap folder itu)
+	Image       string `json:"image,omitempty"`
+	ImageFile   string `json:"imageFile,omitempty"`
+	Description string `json:"description,omitempty"`
+	WikiURL     string `json:"wikiUrl,omitempty"`
 }
 
 type TreeNode struct {
 	Name      string     `json:"name"`
 	Children  []TreeNode `json:"children,omitempty"`
 	Highlight bool       `json:"highlight,omitempty"`
+
+	// Diisi hanya jika request meminta metadata
+	Image       string `json:"image,omitempty"`
+	Description string `json:"description,omitempty"`
+	WikiURL     string `json:"wikiUrl,omitempty"`
 }
 
 var basicElements = []string{"air", "earth", "fire", "water"}
 
-func handleWebSocket(w http.ResponseWriter, r *http.Request) {
+var (
+	errClientCancelled    = errors.New("cancelled by client")
+	errClientDisconnected = errors.New("client disconnected")
+)
+
+func handleWebSocket(datasets *Datasets, w http.ResponseWriter, r *http.Request) {
 	conn, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		log.Println("Upgrade error:", err)
@@ -58,108 +118,282 @@ func handleWebSocket(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	log.Printf("Received request - Element: %s, Algorithm: %s, MaxRecipes: %s",
+	log.Printf("Received request - Element: %s, Algorithm: %s, MaxRecipes: %d",
 		reqData.Target, reqData.Algorithm, reqData.MaxRecipes)
 
-	conn.WriteJSON(map[string]interface{}{
-		"status":  "Processing",
-		"message": "Loading elements data",
-	})
-
-	data, err := ioutil.ReadFile("data/elements.json")
+	// Graph diambil sekali per request, jadi reload di tengah pencarian tidak berpengaruh
+	store, err := datasets.Store(reqData.Dataset)
 	if err != nil {
-		log.Fatalf("Failed to read elements.json: %v", err)
 		conn.WriteJSON(map[string]interface{}{
-			"error": "Failed to read elements data",
+			"status":   "Error",
+			"error":    err.Error(),
+			"datasets": datasets.names,
 		})
 		return
 	}
+	graph := store.Graph()
 
-	var elements []Element
-	if err := json.Unmarshal(data, &elements); err != nil {
-		log.Fatalf("Failed to parse JSON: %v", err)
+	if reqData.Objective, err = parseObjective(reqData.Objective); err != nil {
 		conn.WriteJSON(map[string]interface{}{
-			"error": "Failed to parse elements data",
+			"status": "Error",
+			"error":  err.Error(),
 		})
 		return
 	}
 
-	elementMap = make(map[string]Element)
-	for _, e := range elements {
-		elementMap[strings.ToLower(e.Name)] = e
+	if len(reqData.Targets) > 0 {
+		handlePlanWebSocket(conn, graph, reqData)
+		return
 	}
 
-	var recipePlans []TreeNode
-
-	var nodesVisited int
-	startTime := time.Now()
-	fmt.Printf("Delay: %d\n", reqData.Delay)
-
-	if reqData.Algorithm == "BFS" {
-
+	solver, ok := lookupSolver(reqData.Algorithm)
+	if !ok {
 		conn.WriteJSON(map[string]interface{}{
-			"status":  "Starting BFS",
-			"message": "Initializing search algorithm",
+			"status":     "Error",
+			"error":      fmt.Sprintf("Unknown algorithm %q", reqData.Algorithm),
+			"algorithms": solverNames(),
 		})
+		return
+	}
 
-		if reqData.LiveUpdate {
-			recipePlans = bfsMultipleLive(elementMap, strings.ToLower(reqData.Target), reqData.MaxRecipes, reqData.Delay, conn)
-			log.Println("BFS Live Update")
-		} else {
-			recipePlans, nodesVisited = bfsMultiple(elementMap, strings.ToLower(reqData.Target), reqData.MaxRecipes)
-		}
-
-	} else if reqData.Algorithm == "DFS" {
+	startTime := time.Now()
 
-		conn.WriteJSON(map[string]interface{}{
-			"status":  "Starting DFS",
-			"message": "Initializing search algorithm",
-		})
+	conn.WriteJSON(map[string]interface{}{
+		"status":  "Starting " + solver.Label(),
+		"message": "Initializing search algorithm",
+	})
 
-		if reqData.LiveUpdate {
-			recipePlans, nodesVisited = dfsMultipleLive(strings.ToLower(reqData.Target), reqData.MaxRecipes, reqData.Delay, conn)
-		} else {
-			recipePlans, nodesVisited = dfsMultiple(strings.ToLower(reqData.Target), reqData.MaxRecipes)
-		}
+	opts := SolverOptions{
+		MaxRecipes:    reqData.recipeLimit(),
+		Delay:         reqData.Delay,
+		Limits:        reqData.limits(),
+		Objective:     reqData.Objective,
+		Inventory:     reqData.Inventory,
+		Deterministic: reqData.Deterministic,
+		Seed:          reqData.Seed,
 	}
-	// } else if reqData.Algorithm == "BID" {
 
-	// 	conn.WriteJSON(map[string]interface{}{
-	// 		"status":  "Starting Bidirectional Search",
-	// 		"message": "Initializing search algorithm",
-	// 	})
+	ctx, stop := context.WithTimeout(context.Background(), opts.Limits.Timeout)
+	defer stop()
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	go watchClient(conn, cancel)
 
+	var result SearchResult
+	target, _ := graph.Resolve(reqData.Target)
 	if reqData.LiveUpdate {
-		recipePlans, nodesVisited = bidirectionalMultiple(strings.ToLower(reqData.Target), reqData.MaxRecipes, min(reqData.MaxRecipes*1000, 20000))
+		result = solver.SolveLive(ctx, graph, target, opts, conn)
 	} else {
-		recipePlans, nodesVisited = bidirectionalMultiple(strings.ToLower(reqData.Target), reqData.MaxRecipes, min(reqData.MaxRecipes*1000, 20000))
+		result = solver.Solve(ctx, graph, target, opts)
 	}
+	recipePlans := result.Trees
 
-	
 	elapsed := time.Since(startTime)
-	fmt.Printf("Ditemukan %d resep via %s.\n", len(recipePlans), reqData.Algorithm)
+	log.Printf("Found %d recipe plans via %s in %v\n", len(recipePlans), reqData.Algorithm, elapsed)
+
+	if ctx.Err() != nil {
+		log.Printf("Search for %s stopped: %v\n", reqData.Target, context.Cause(ctx))
+		response := map[string]interface{}{
+			"status":         "Cancelled",
+			"message":        fmt.Sprintf("Search stopped (%v), found %d recipe plans", context.Cause(ctx), len(recipePlans)),
+			"duration":       formatTime(elapsed.String()),
+			"treeData":       recipePlans,
+			"nodes":          result.NodesVisited,
+			"truncatedBy":    result.TruncatedBy,
+			"datasetVersion": graph.Version,
+		}
+		if reqData.Sequence {
+			response["sequences"] = graph.treeSequences(recipePlans)
+		}
+		if reqData.Metadata {
+			response["treeData"] = graph.withMetadata(recipePlans)
+		}
+		conn.WriteJSON(response)
+		return
+	}
 
 	if len(recipePlans) == 0 {
+		response := map[string]interface{}{
+			"status":         "Completed",
+			"message":        "No recipe plans found",
+			"duration":       formatTime(elapsed.String()),
+			"nodes":          result.NodesVisited,
+			"truncatedBy":    result.TruncatedBy,
+			"datasetVersion": graph.Version,
+		}
+		if _, ok := graph.Element(target); !ok {
+			response["suggestions"] = graph.suggest(target, 5)
+		}
+		conn.WriteJSON(response)
+		return
+	}
+
+	response := map[string]interface{}{
+		"status":         "Completed",
+		"message":        fmt.Sprintf("Found %d recipe plans", len(recipePlans)),
+		"totalRecipes":   formatCount(graph.RecipeCount(target)),
+		"duration":       formatTime(elapsed.String()),
+		"treeData":       recipePlans,
+		"nodes":          result.NodesVisited,
+		"truncatedBy":    result.TruncatedBy,
+		"datasetVersion": graph.Version,
+	}
+	if reqData.Sequence {
+		response["sequences"] = graph.treeSequences(recipePlans)
+	}
+	if reqData.Metadata {
+		response["treeData"] = graph.withMetadata(recipePlans)
+	}
+	conn.WriteJSON(response)
+}
+
+// handlePlanWebSocket mengirim satu rencana gabungan untuk semua elemen di reqData.Targets
+func handlePlanWebSocket(conn *websocket.Conn, graph *RecipeGraph, reqData RequestData) {
+	if reqData.Target != "" {
+		reqData.Targets = append([]string{reqData.Target}, reqData.Targets...)
+	}
+	targets, unknown := graph.normalizeTargets(reqData.Targets)
+	if len(unknown) > 0 {
 		conn.WriteJSON(map[string]interface{}{
-			"status":  "Completed",
-			"message": "No recipe plans found",
+			"status": "Error",
+			"error":  fmt.Sprintf("Unknown elements %q", unknown),
 		})
 		return
 	}
 
-	fmt.Println("Waktu eksekusi: ", elapsed)
-
 	conn.WriteJSON(map[string]interface{}{
-		"status":   "Completed",
-		"message":  fmt.Sprintf("Found %d recipe plans", len(recipePlans)),
-		"duration": formatTime(elapsed.String()),
-		"treeData": recipePlans,
-		"nodes":    nodesVisited,
+		"status":  "Starting Combined Plan Search",
+		"message": fmt.Sprintf("Planning %d targets", len(targets)),
 	})
+
+	opts := SolverOptions{Limits: reqData.limits(), Inventory: reqData.Inventory}
+	ctx, stop := context.WithTimeout(context.Background(), opts.Limits.Timeout)
+	defer stop()
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	go watchClient(conn, cancel)
+
+	startTime := time.Now()
+	plan := planTargets(ctx, graph, targets, opts)
+	elapsed := time.Since(startTime)
+
+	status := "Completed"
+	if ctx.Err() != nil {
+		status = "Cancelled"
+	}
+	response := map[string]interface{}{
+		"status":         status,
+		"message":        planMessage(plan, targets),
+		"targets":        graph.displayNames(targets),
+		"steps":          graph.displaySteps(plan.Steps),
+		"treeData":       plan.Trees,
+		"duration":       formatTime(elapsed.String()),
+		"nodes":          plan.NodesVisited,
+		"truncatedBy":    plan.TruncatedBy,
+		"datasetVersion": graph.Version,
+	}
+	if reqData.Sequence {
+		// Kuncinya sama dengan response satu target; rencana gabungan hanya punya satu daftar langkah
+		response["sequences"] = [][]CraftStep{graph.craftSequence(plan.Steps)}
+	}
+	if reqData.Metadata {
+		response["treeData"] = graph.withMetadata(plan.Trees)
+	}
+	conn.WriteJSON(response)
 }
 
-func main() {
-	http.HandleFunc("/ws", handleWebSocket)
+// runServe menjalankan server HTTP/WebSocket, perintah default jika program dijalankan tanpa argumen
+func runServe(args []string) {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	dataPath := fs.String("data", defaultDataPath(), "path to elements.json (env ELEMENTS_PATH)")
+	defaultDataset := fs.String("dataset", gameLA2, "name of the dataset loaded from -data, used when a request names none")
+	extraDatasets := fs.String("datasets", os.Getenv("DATASETS"), "more datasets as name=path,name=path, e.g. la1=data/elements_la1.json (env DATASETS)")
+	port := fs.String("port", os.Getenv("PORT"), "port to listen on (env PORT)")
+	assetDir := fs.String("assets", os.Getenv("ASSETS_DIR"), "directory with element images served at /assets/ (default: assets next to -data, env ASSETS_DIR)")
+	strictEnv, _ := strconv.ParseBool(os.Getenv("STRICT_DATASET"))
+	strict := fs.Bool("strict", strictEnv, "refuse to start or reload a dataset with validation errors (env STRICT_DATASET)")
+	ignore := fs.String("ignore", os.Getenv("VALIDATION_IGNORE"), "comma-separated validation codes that do not count as errors (env VALIDATION_IGNORE)")
+	fs.Parse(args)
+
+	ignoreCodes, err := parseIgnoreCodes(*ignore)
+	if err != nil {
+		log.Fatalf("Invalid -ignore: %v", err)
+	}
+
+	paths, err := parseDatasetSpecs(*extraDatasets)
+	if err != nil {
+		log.Fatalf("Invalid -datasets: %v", err)
+	}
+	paths[strings.ToLower(*defaultDataset)] = *dataPath
+	datasets, err := newDatasets(strings.ToLower(*defaultDataset), paths, *strict, ignoreCodes)
+	if err != nil {
+		log.Fatalf("Failed to load elements data: %v", err)
+	}
+	for _, name := range datasets.names {
+		g := datasets.stores[name].Graph()
+		log.Printf("Loaded dataset %s: %d elements from %s (version %s)\n", name, len(g.Elements), paths[name], g.Version)
+	}
+
+	if v := os.Getenv("SEARCH_TIMEOUT"); v != "" {
+		serverLimits.Timeout, err = time.ParseDuration(v)
+		if err != nil {
+			log.Fatalf("Invalid SEARCH_TIMEOUT %q: %v", v, err)
+		}
+	}
+
+	// RELOAD_INTERVAL=0 mematikan pengecekan file otomatis, reload masih bisa lewat /admin/reload
+	reloadInterval := 10 * time.Second
+	if v := os.Getenv("RELOAD_INTERVAL"); v != "" {
+		reloadInterval, err = time.ParseDuration(v)
+		if err != nil {
+			log.Fatalf("Invalid RELOAD_INTERVAL %q: %v", v, err)
+		}
+	}
+	if reloadInterval > 0 {
+		for _, store := range datasets.stores {
+			go store.Watch(reloadInterval, nil)
+		}
+	}
+
+	http.HandleFunc("/ws", func(w http.ResponseWriter, r *http.Request) {
+		handleWebSocket(datasets, w, r)
+	})
+
+	http.HandleFunc("/api/search", func(w http.ResponseWriter, r *http.Request) {
+		handleSearchAPI(datasets, w, r)
+	})
+
+	http.HandleFunc("/api/export", func(w http.ResponseWriter, r *http.Request) {
+		handleExport(datasets, w, r)
+	})
+	http.HandleFunc("/api/explore", func(w http.ResponseWriter, r *http.Request) {
+		handleExplore(datasets, w, r)
+	})
+
+	http.HandleFunc("GET /api/datasets", func(w http.ResponseWriter, r *http.Request) {
+		handleListDatasets(datasets, w, r)
+	})
+	http.HandleFunc("GET /api/elements", func(w http.ResponseWriter, r *http.Request) {
+		handleListElements(datasets, w, r)
+	})
+	http.HandleFunc("GET /api/elements/suggest", func(w http.ResponseWriter, r *http.Request) {
+		handleSuggestElements(datasets, w, r)
+	})
+	http.HandleFunc("GET /api/elements/{name}", func(w http.ResponseWriter, r *http.Request) {
+		handleGetElement(datasets, w, r)
+	})
+	http.HandleFunc("GET /api/elements/{name}/count", func(w http.ResponseWriter, r *http.Request) {
+		handleCountRecipes(datasets, w, r)
+	})
+
+	if *assetDir == "" {
+		*assetDir = filepath.Join(filepath.Dir(*dataPath), "assets")
+	}
+	http.Handle("GET "+assetsPrefix, http.StripPrefix(assetsPrefix, http.FileServer(http.Dir(*assetDir))))
+
+	http.HandleFunc("/admin/reload", func(w http.ResponseWriter, r *http.Request) {
+		handleReload(datasets, w, r)
+	})
 
 	http.HandleFunc("/data", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "http://localhost:3000")
@@ -169,31 +403,94 @@ func main() {
 		http.ServeFile(w, r, "../public/tree.json")
 	})
 
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+	if *port == "" {
+		*port = "8080"
 	}
 
-	log.Printf("Server started at http://localhost:%s\n", port)
-	log.Fatal(http.ListenAndServe(":"+port, nil))
+	log.Printf("Server started at http://localhost:%s\n", *port)
+	log.Fatal(http.ListenAndServe(":"+*port, nil))
+}
+
+// watchClient membaca pesan dari client selama pencarian berjalan.
+// Pencarian dibatalkan jika client mengirim {"type":"cancel"} atau koneksi terputus.
+func watchClient(conn *websocket.Conn, cancel context.CancelCauseFunc) {
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			cancel(errClientDisconnected)
+			return
+		}
+
+		var msg struct {
+			Type string `json:"type"`
+		}
+		if json.Unmarshal(data, &msg) == nil && msg.Type == "cancel" {
+			cancel(errClientCancelled)
+		}
+	}
+}
+
+func handleReload(datasets *Datasets, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	// Tanpa ADMIN_TOKEN reload hanya boleh dari mesin yang sama, karena setiap reload mem-parse ulang dataset
+	token := os.Getenv("ADMIN_TOKEN")
+	if token == "" && !isLoopback(r.RemoteAddr) {
+		http.Error(w, "forbidden: set ADMIN_TOKEN to reload from another host", http.StatusForbidden)
+		return
+	}
+	if token != "" && subtle.ConstantTimeCompare([]byte(r.Header.Get("X-Admin-Token")), []byte(token)) != 1 {
+		http.Error(w, "missing or invalid X-Admin-Token", http.StatusUnauthorized)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	// ?dataset= memilih dataset yang di-reload, tanpa parameter yang di-reload dataset default
+	store, err := datasets.Store(r.URL.Query().Get("dataset"))
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]interface{}{"error": err.Error()})
+		return
+	}
+	g, err := store.Reload()
+	if err != nil {
+		log.Printf("Dataset reload failed: %v\n", err)
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error":          err.Error(),
+			"datasetVersion": store.Graph().Version,
+		})
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":         "Reloaded",
+		"datasetVersion": g.Version,
+		"elements":       len(g.Elements),
+	})
+}
+
+// isLoopback melaporkan apakah remoteAddr (host:port dari http.Request) berasal dari localhost
+func isLoopback(remoteAddr string) bool {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
 }
 
 func isBasicElement(name string) bool {
 	for _, b := range basicElements {
-		if strings.ToLower(name) == strings.ToLower(b) {
+		if canonicalName(name) == b {
 			return true
 		}
 	}
 	return false
 }
 
-func capitalize(s string) string {
-	if len(s) == 0 {
-		return s
-	}
-	return strings.ToUpper(s[:1]) + s[1:]
-}
-
 func formatTime(time string) string {
 	result := ""
 	for i, char := range time {
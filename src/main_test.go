@@ -0,0 +1,127 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// TestWebSocketSingleTerminalStatus: client boleh berhenti membaca di status akhir pertama, jadi setiap
+// solver (live maupun tidak) harus menghasilkan tepat satu Completed atau Cancelled, yaitu yang membawa treeData
+func TestWebSocketSingleTerminalStatus(t *testing.T) {
+	datasets := newTestDatasets(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handleWebSocket(datasets, w, r)
+	}))
+	defer srv.Close()
+	url := "ws" + strings.TrimPrefix(srv.URL, "http")
+
+	for _, name := range solverNames() {
+		for _, live := range []bool{false, true} {
+			conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+			err = conn.WriteJSON(RequestData{Target: "wall", Algorithm: name, MaxRecipes: 3, LiveUpdate: live})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var terminal []map[string]interface{}
+			for {
+				var msg map[string]interface{}
+				if err := conn.ReadJSON(&msg); err != nil {
+					break
+				}
+				if msg["status"] == "Completed" || msg["status"] == "Cancelled" {
+					terminal = append(terminal, msg)
+				}
+			}
+			conn.Close()
+
+			if len(terminal) != 1 {
+				t.Errorf("%s live=%v: %d terminal statuses, want 1: %v", name, live, len(terminal), terminal)
+				continue
+			}
+			if trees, _ := terminal[0]["treeData"].([]interface{}); len(trees) == 0 {
+				t.Errorf("%s live=%v: terminal status has no treeData: %v", name, live, terminal[0])
+			}
+		}
+	}
+}
+
+// TestWebSocketTerminalFields: setiap status akhir punya field yang sama, termasuk pencarian tanpa hasil
+func TestWebSocketTerminalFields(t *testing.T) {
+	datasets := newTestDatasets(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handleWebSocket(datasets, w, r)
+	}))
+	defer srv.Close()
+	url := "ws" + strings.TrimPrefix(srv.URL, "http")
+
+	for _, target := range []string{"wall", "wal"} {
+		conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+		if err := conn.WriteJSON(RequestData{Target: target, Algorithm: "BFS"}); err != nil {
+			t.Fatal(err)
+		}
+		var last map[string]interface{}
+		for {
+			var msg map[string]interface{}
+			if err := conn.ReadJSON(&msg); err != nil {
+				break
+			}
+			last = msg
+		}
+		conn.Close()
+
+		for _, field := range []string{"status", "message", "duration", "nodes", "truncatedBy", "datasetVersion"} {
+			if _, ok := last[field]; !ok {
+				t.Errorf("%s: terminal response %v has no %q", target, last, field)
+			}
+		}
+	}
+}
+
+// TestReloadAuth: tanpa ADMIN_TOKEN hanya localhost yang boleh reload; dengan ADMIN_TOKEN semua request
+// harus mengirim token yang sama
+func TestReloadAuth(t *testing.T) {
+	datasets := newTestDatasets(t)
+
+	tests := []struct {
+		name       string
+		token      string
+		remoteAddr string
+		header     string
+		want       int
+	}{
+		{"no token, remote client", "", "192.0.2.1:1234", "", http.StatusForbidden},
+		{"no token, remote client sending a token", "", "192.0.2.1:1234", "guess", http.StatusForbidden},
+		{"no token, localhost", "", "127.0.0.1:1234", "", http.StatusOK},
+		{"no token, IPv6 localhost", "", "[::1]:1234", "", http.StatusOK},
+		{"token, missing header", "secret", "127.0.0.1:1234", "", http.StatusUnauthorized},
+		{"token, wrong header", "secret", "192.0.2.1:1234", "guess", http.StatusUnauthorized},
+		{"token, right header", "secret", "192.0.2.1:1234", "secret", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Setenv("ADMIN_TOKEN", tt.token)
+		req := httptest.NewRequest("POST", "/admin/reload", nil)
+		req.RemoteAddr = tt.remoteAddr
+		if tt.header != "" {
+			req.Header.Set("X-Admin-Token", tt.header)
+		}
+		rec := httptest.NewRecorder()
+		handleReload(datasets, rec, req)
+		if rec.Code != tt.want {
+			t.Errorf("%s: status %d, want %d: %s", tt.name, rec.Code, tt.want, rec.Body)
+		}
+	}
+}
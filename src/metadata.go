@@ -0,0 +1,102 @@
+package main
+
+import (
+	"io"
+	"path"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// assetsPrefix adalah path URL tempat server menyajikan folder assets
+const assetsPrefix = "/assets/"
+
+const maxDescriptionLength = 300
+
+// imageURL mengembalikan salinan lokal gambar elemen jika ada, jika tidak URL wiki-nya
+func imageURL(meta ElementMeta) string {
+	if meta.ImageFile != "" {
+		return assetsPrefix + meta.ImageFile
+	}
+	return meta.Image
+}
+
+// withMetadata menyalin pohon resep dan mengisi gambar, deskripsi dan link wiki setiap node.
+// Pohon hasil solver tidak diubah karena bisa dipakai bersama oleh response lain.
+func (g *RecipeGraph) withMetadata(trees []TreeNode) []TreeNode {
+	var annotate func(node TreeNode) TreeNode
+	annotate = func(node TreeNode) TreeNode {
+		if e, ok := g.Element(node.Name); ok {
+			node.Image = imageURL(e.ElementMeta)
+			node.Description = e.Description
+			node.WikiURL = e.WikiURL
+		}
+		if len(node.Children) > 0 {
+			children := make([]TreeNode, len(node.Children))
+			for i, child := range node.Children {
+				children[i] = annotate(child)
+			}
+			node.Children = children
+		}
+		return node
+	}
+
+	out := make([]TreeNode, len(trees))
+	for i, tree := range trees {
+		out[i] = annotate(tree)
+	}
+	return out
+}
+
+// parseElementMeta membaca gambar infobox, paragraf pertama dan URL kanonik dari halaman elemen.
+// pageURL dipakai jika halaman tidak punya link kanonik.
+func parseElementMeta(r io.Reader, pageURL string) (ElementMeta, error) {
+	doc, err := goquery.NewDocumentFromReader(r)
+	if err != nil {
+		return ElementMeta{}, err
+	}
+	meta := ElementMeta{WikiURL: pageURL}
+
+	if href, ok := doc.Find(`link[rel="canonical"]`).Attr("href"); ok && href != "" {
+		meta.WikiURL = href
+	}
+
+	// Fandom memakai lazy loading, jadi URL asli ada di data-src dan src hanya placeholder
+	img := doc.Find("aside.portable-infobox .pi-image img, aside.portable-infobox img").First()
+	for _, attr := range []string{"data-src", "src"} {
+		if v, ok := img.Attr(attr); ok && strings.HasPrefix(v, "http") {
+			meta.Image = v
+			break
+		}
+	}
+	if meta.Image == "" {
+		meta.Image, _ = doc.Find(`meta[property="og:image"]`).Attr("content")
+	}
+
+	doc.Find("div.mw-parser-output > p").EachWithBreak(func(_ int, p *goquery.Selection) bool {
+		meta.Description = strings.Join(strings.Fields(p.Text()), " ")
+		return meta.Description == ""
+	})
+	if meta.Description == "" {
+		meta.Description, _ = doc.Find(`meta[property="og:description"]`).Attr("content")
+	}
+	if runes := []rune(meta.Description); len(runes) > maxDescriptionLength {
+		meta.Description = strings.TrimSpace(string(runes[:maxDescriptionLength-1])) + "…"
+	}
+	return meta, nil
+}
+
+// assetFileName membuat nama file gambar di folder assets, misalnya "la2/steam_engine.png".
+// Ekstensi diambil dari URL wiki, yang biasanya berbentuk .../Mud.png/revision/latest?cb=...
+func assetFileName(game, element, imageURL string) string {
+	ext := ".png"
+	u := strings.SplitN(imageURL, "?", 2)[0]
+	for _, segment := range strings.Split(u, "/") {
+		switch e := strings.ToLower(path.Ext(segment)); e {
+		case ".png", ".jpg", ".jpeg", ".gif", ".svg", ".webp":
+			ext = e
+		}
+	}
+	name := unsafeFileChars.ReplaceAllString(strings.ToLower(element), "_")
+	return game + "/" + name + ext
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseElementMeta(t *testing.T) {
+	f, err := os.Open(filepath.Join("testdata", "parser", "mud.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	got, err := parseElementMeta(f, "https://little-alchemy.fandom.com/wiki/Mud_(page)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := ElementMeta{
+		Image:       "https://static.wikia.nocookie.net/little-alchemy/images/5/5e/Mud_2.png/revision/latest/scale-to-width-down/100?cb=20160721",
+		Description: "Mud is one of the elements in Little Alchemy and Little Alchemy 2.",
+		WikiURL:     "https://little-alchemy.fandom.com/wiki/Mud",
+	}
+	if got != want {
+		t.Errorf("got %+v\nwant %+v", got, want)
+	}
+
+	if file := assetFileName(gameLA2, "Steam engine", got.Image); file != "la2/steam_engine.png" {
+		t.Errorf("assetFileName = %q", file)
+	}
+}
+
+// TestParseElementMetaGolden membaca metadata dari setiap halaman di testdata/parser dan membandingkannya
+// dengan <nama>.meta.golden.json. Flag -update dari TestParseRecipes juga menulis ulang file ini.
+func TestParseElementMetaGolden(t *testing.T) {
+	pages, err := filepath.Glob(filepath.Join("testdata", "parser", "*.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, page := range pages {
+		target := parserTarget(page)
+		t.Run(target, func(t *testing.T) {
+			f, err := os.Open(page)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+
+			pageURL := "https://little-alchemy.fandom.com/wiki/" + strings.ReplaceAll(target, " ", "_")
+			got, err := parseElementMeta(f, pageURL)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			golden := strings.TrimSuffix(page, ".html") + ".meta.golden.json"
+			if *update {
+				data, err := json.MarshalIndent(got, "", "  ")
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(golden, append(data, '\n'), 0644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			data, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("%v (run with -update to create it)", err)
+			}
+			var want ElementMeta
+			if err := json.Unmarshal(data, &want); err != nil {
+				t.Fatalf("%s: %v", golden, err)
+			}
+			if got != want {
+				t.Errorf("metadata for %s changed\n got: %+v\nwant: %+v", target, got, want)
+			}
+		})
+	}
+}
+
+func TestWithMetadata(t *testing.T) {
+	elements := append([]Element{}, testElements...)
+	for i, e := range elements {
+		switch e.Name {
+		case "Mud":
+			// Gambar yang sudah diunduh disajikan dari /assets/
+			elements[i].ElementMeta = ElementMeta{Image: "https://wiki/Mud.png", ImageFile: "la2/mud.png", Description: "Wet earth.", WikiURL: "https://wiki/Mud"}
+		case "Brick":
+			elements[i].ElementMeta = ElementMeta{Image: "https://wiki/Brick.png", WikiURL: "https://wiki/Brick"}
+		}
+	}
+	graph := newTestGraph(t, elements)
+
+	tree := TreeNode{Name: "Brick", Children: []TreeNode{
+		{Name: "Fire"},
+		{Name: "Mud", Children: []TreeNode{{Name: "Earth"}, {Name: "Water"}}},
+	}}
+	want := TreeNode{Name: "Brick", Image: "https://wiki/Brick.png", WikiURL: "https://wiki/Brick", Children: []TreeNode{
+		{Name: "Fire"},
+		{Name: "Mud", Image: "/assets/la2/mud.png", Description: "Wet earth.", WikiURL: "https://wiki/Mud", Children: []TreeNode{{Name: "Earth"}, {Name: "Water"}}},
+	}}
+
+	got := graph.withMetadata([]TreeNode{tree})
+	if !reflect.DeepEqual(got, []TreeNode{want}) {
+		t.Errorf("withMetadata:\n got %+v\nwant %+v", got, want)
+	}
+	// Pohon asli dari solver tidak boleh ikut berubah
+	if tree.Image != "" || tree.Children[1].Description != "" {
+		t.Errorf("withMetadata modified its input: %+v", tree)
+	}
+}
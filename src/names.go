@@ -0,0 +1,243 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// aliasFile adalah tabel alias opsional di folder yang sama dengan dataset, berisi {"alias": "elemen"}
+const aliasFile = "aliases.json"
+
+// builtinAliases berisi ejaan lain yang umum; alias hanya dipakai jika elemen tujuannya ada di dataset
+var builtinAliases = map[string]string{
+	"aeroplane":       "airplane",
+	"paper aeroplane": "paper airplane",
+	"armour":          "armor",
+	"doughnut":        "donut",
+	"yoghurt":         "yogurt",
+	"plough":          "plow",
+	"mould":           "mold",
+	"flying saucer":   "ufo",
+	"lightsaber":      "light sword",
+	"light saber":     "light sword",
+	"pc":              "computer",
+	"motorbike":       "motorcycle",
+	"bike":            "bicycle",
+}
+
+var (
+	// Halaman wiki kadang memberi akhiran seperti "Steam (Little Alchemy 2)" pada nama elemen
+	disambiguationSuffix = regexp.MustCompile(`\s*\([^()]*\)\s*$`)
+	// Sisa link gambar dari wiki, misalnya "File:Spray 2.svg Spray"
+	wikiFilePrefix = regexp.MustCompile(`(?i)^file:.*?\.(png|svg|jpe?g|gif|webp)\s*`)
+	quoteReplacer  = strings.NewReplacer("’", "'", "‘", "'", "“", `"`, "”", `"`, "_", " ")
+)
+
+// cleanDisplayName membersihkan nama dari wiki tanpa mengubah huruf besar/kecilnya
+func cleanDisplayName(name string) string {
+	name = quoteReplacer.Replace(name)
+	name = wikiFilePrefix.ReplaceAllString(strings.TrimSpace(name), "")
+	for disambiguationSuffix.MatchString(name) {
+		stripped := disambiguationSuffix.ReplaceAllString(name, "")
+		if strings.TrimSpace(stripped) == "" {
+			break
+		}
+		name = stripped
+	}
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// canonicalName adalah kunci yang dipakai untuk semua pencarian nama elemen: nama yang sudah
+// dibersihkan, spasi dirapikan dan lowercase (termasuk huruf non-ASCII).
+func canonicalName(name string) string {
+	if isCanonical(name) {
+		return name
+	}
+	return strings.ToLower(cleanDisplayName(name))
+}
+
+// isCanonical adalah jalur cepat untuk nama yang sudah berupa kunci, yang paling sering dipanggil solver
+func isCanonical(name string) bool {
+	if name == "" || name[0] == ' ' || name[len(name)-1] == ' ' {
+		return false
+	}
+	prevSpace := false
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		switch {
+		case c >= 'a' && c <= 'z', c >= '0' && c <= '9', c == '-', c == '\'', c == '.', c == '!', c == '&':
+			prevSpace = false
+		case c == ' ' && !prevSpace:
+			prevSpace = true
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// capitalize membuat huruf pertama kapital, aman untuk huruf non-ASCII
+func capitalize(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	if r == utf8.RuneError {
+		return s
+	}
+	return string(unicode.ToUpper(r)) + s[size:]
+}
+
+// NameResolver mengubah nama dari request, resep atau inventory menjadi kunci elemen di dataset
+type NameResolver struct {
+	// display berisi nama asli dataset untuk setiap kunci
+	display map[string]string
+	// aliases berisi alias kanonik -> kunci elemen
+	aliases map[string]string
+}
+
+func newNameResolver(elements []Element, aliases map[string]string) *NameResolver {
+	r := &NameResolver{
+		display: make(map[string]string, len(elements)),
+		aliases: make(map[string]string, len(aliases)),
+	}
+	for _, e := range elements {
+		key := canonicalName(e.Name)
+		if _, ok := r.display[key]; !ok && key != "" {
+			r.display[key] = cleanDisplayName(e.Name)
+		}
+	}
+	for alias, target := range aliases {
+		alias, target = canonicalName(alias), canonicalName(target)
+		if _, exists := r.display[alias]; exists {
+			continue // nama elemen asli selalu menang atas alias
+		}
+		if _, ok := r.display[target]; ok {
+			r.aliases[alias] = target
+		}
+	}
+	return r
+}
+
+// Resolve mengembalikan kunci elemen untuk name. Urutannya: nama persis, tabel alias, lalu bentuk
+// tunggal dari kata terakhir ("puddles" -> "puddle", "berries" -> "berry"). Jika tidak ada yang cocok,
+// hasilnya adalah nama kanonik dengan ok = false.
+func (r *NameResolver) Resolve(name string) (string, bool) {
+	key := canonicalName(name)
+	if _, ok := r.display[key]; ok {
+		return key, true
+	}
+	if target, ok := r.aliases[key]; ok {
+		return target, true
+	}
+	for _, s := range singularForms(key) {
+		if _, ok := r.display[s]; ok {
+			return s, true
+		}
+		if target, ok := r.aliases[s]; ok {
+			return target, true
+		}
+	}
+	return key, false
+}
+
+// Display mengembalikan nama asli dataset untuk sebuah kunci
+func (r *NameResolver) Display(key string) string {
+	if name, ok := r.display[key]; ok {
+		return name
+	}
+	return capitalize(key)
+}
+
+func singularForms(key string) []string {
+	var forms []string
+	switch {
+	case strings.HasSuffix(key, "ies"):
+		forms = append(forms, strings.TrimSuffix(key, "ies")+"y")
+	case strings.HasSuffix(key, "es"):
+		forms = append(forms, strings.TrimSuffix(key, "es"), strings.TrimSuffix(key, "s"))
+	case strings.HasSuffix(key, "s") && !strings.HasSuffix(key, "ss"):
+		forms = append(forms, strings.TrimSuffix(key, "s"))
+	}
+	return forms
+}
+
+// loadAliases membaca builtinAliases ditambah aliases.json di dir jika ada
+func loadAliases(dir string) (map[string]string, error) {
+	data, err := readAliasFile(dir)
+	if err != nil {
+		return nil, err
+	}
+	return parseAliases(dir, data)
+}
+
+// readAliasFile mengembalikan isi aliases.json di dir, atau nil jika file itu tidak ada
+func readAliasFile(dir string) ([]byte, error) {
+	data, err := os.ReadFile(filepath.Join(dir, aliasFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	return data, err
+}
+
+// parseAliases menggabungkan builtinAliases dengan isi aliases.json dari dir (boleh nil)
+func parseAliases(dir string, data []byte) (map[string]string, error) {
+	aliases := make(map[string]string, len(builtinAliases))
+	for k, v := range builtinAliases {
+		aliases[k] = v
+	}
+	if data == nil {
+		return aliases, nil
+	}
+
+	var extra map[string]string
+	if err := json.Unmarshal(data, &extra); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", filepath.Join(dir, aliasFile), err)
+	}
+	for k, v := range extra {
+		aliases[k] = v
+	}
+	return aliases, nil
+}
+
+// normalizeElements mengembalikan salinan elements dengan nama yang sudah dibersihkan dan setiap bahan
+// resep diganti nama elemen hasil Resolve, sehingga "Puddles" atau "Steam (Little Alchemy 2)" menunjuk
+// ke elemen yang sama. Bahan yang tidak dikenal tetap ditulis (sudah dibersihkan) supaya validator bisa
+// melaporkannya.
+func normalizeElements(elements []Element, aliases map[string]string) []Element {
+	resolver := newNameResolver(elements, aliases)
+	out := make([]Element, len(elements))
+	for i, e := range elements {
+		e.Name = cleanDisplayName(e.Name)
+		var recipes [][]string
+		if e.Recipes != nil {
+			recipes = make([][]string, 0, len(e.Recipes))
+		}
+		for _, recipe := range e.Recipes {
+			resolved := make([]string, len(recipe))
+			for j, ing := range recipe {
+				if key, ok := resolver.Resolve(ing); ok {
+					resolved[j] = resolver.Display(key)
+				} else {
+					resolved[j] = cleanDisplayName(ing)
+				}
+			}
+			recipes = append(recipes, resolved)
+		}
+		e.Recipes = recipes
+		out[i] = e
+	}
+	return out
+}
+
+// displayName mengembalikan nama asli elemen dengan kunci key, dipakai solver untuk TreeNode.Name
+func displayName(elementMap map[string]Element, key string) string {
+	if e, ok := elementMap[key]; ok && e.Name != "" {
+		return e.Name
+	}
+	return capitalize(key)
+}
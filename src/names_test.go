@@ -0,0 +1,127 @@
+package main
+
+import "testing"
+
+func TestCleanAndCanonicalName(t *testing.T) {
+	tests := []struct {
+		in, clean, canonical string
+	}{
+		{"Steam", "Steam", "steam"},
+		{"Steam (Little Alchemy 2)", "Steam", "steam"},
+		{"Kraken (Myths and Monsters) (Little Alchemy 2)", "Kraken", "kraken"},
+		// Nama yang seluruhnya dalam kurung tidak dikosongkan
+		{"(Unknown)", "(Unknown)", "(unknown)"},
+		{"File:Spray 2.svg Spray", "Spray", "spray"},
+		{"file:Fire.PNG Fire", "Fire", "fire"},
+		{"  Hot   air ", "Hot air", "hot air"},
+		{"Steam_engine", "Steam engine", "steam engine"},
+		{"Rock ’n’ roll", "Rock 'n' roll", "rock 'n' roll"},
+		{"ÉCLAIR", "ÉCLAIR", "éclair"},
+	}
+	for _, tt := range tests {
+		if got := cleanDisplayName(tt.in); got != tt.clean {
+			t.Errorf("cleanDisplayName(%q) = %q, want %q", tt.in, got, tt.clean)
+		}
+		if got := canonicalName(tt.in); got != tt.canonical {
+			t.Errorf("canonicalName(%q) = %q, want %q", tt.in, got, tt.canonical)
+		}
+	}
+}
+
+func TestSingularForms(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"berries", []string{"berry"}},
+		{"puddles", []string{"puddl", "puddle"}},
+		{"boxes", []string{"box", "boxe"}},
+		{"clouds", []string{"cloud"}},
+		{"glass", nil},
+		{"steam", nil},
+	}
+	for _, tt := range tests {
+		got := singularForms(tt.in)
+		if len(got) != len(tt.want) {
+			t.Errorf("singularForms(%q) = %q, want %q", tt.in, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("singularForms(%q) = %q, want %q", tt.in, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestNameResolver(t *testing.T) {
+	elements := []Element{
+		{Name: "Berry"}, {Name: "Puddle"}, {Name: "Box"}, {Name: "Cloud"},
+		{Name: "Glass"}, {Name: "Lens"}, {Name: "Bus"}, {Name: "Hummus"},
+		{Name: "Mold"}, {Name: "Mould"}, {Name: "Steam (Little Alchemy 2)"}, {Name: "Water"},
+		{Name: "Computer"},
+	}
+	aliases := map[string]string{
+		"mould":   "mold",     // "Mould" juga elemen asli, jadi alias ini diabaikan
+		"steam":   "water",    // sama, nama asli menang
+		"pc":      "computer", // alias biasa
+		"Laptops": "computer",
+		"kettle":  "unobtainium", // tujuan tidak ada, alias diabaikan
+	}
+	r := newNameResolver(elements, aliases)
+
+	tests := []struct {
+		in     string
+		want   string
+		wantOK bool
+	}{
+		{"berries", "berry", true},
+		{"Puddles", "puddle", true},
+		{"boxes", "box", true},
+		{"clouds", "cloud", true},
+		// Nama asli yang berakhiran s tidak dipotong
+		{"glass", "glass", true},
+		{"Lens", "lens", true},
+		{"bus", "bus", true},
+		{"hummus", "hummus", true},
+		{"glasses", "glass", true},
+		{"mould", "mould", true},
+		{"Steam", "steam", true},
+		{"STEAM (Little Alchemy 2)", "steam", true},
+		{"PC", "computer", true},
+		{"laptops", "computer", true},
+		{"kettle", "kettle", false},
+		{"unobtainium", "unobtainium", false},
+	}
+	for _, tt := range tests {
+		got, ok := r.Resolve(tt.in)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("Resolve(%q) = %q, %v, want %q, %v", tt.in, got, ok, tt.want, tt.wantOK)
+		}
+	}
+
+	if got := r.Display("steam"); got != "Steam" {
+		t.Errorf("Display(steam) = %q, want the cleaned dataset name Steam", got)
+	}
+	if got := r.Display("hot air"); got != "Hot air" {
+		t.Errorf("Display(hot air) = %q, want Hot air", got)
+	}
+}
+
+func TestCapitalize(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"steam engine", "Steam engine"},
+		{"rock 'n' roll", "Rock 'n' roll"},
+		{"éclair", "Éclair"},
+		{"ölkanne", "Ölkanne"},
+		{"Already", "Already"},
+		{"1up", "1up"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := capitalize(tt.in); got != tt.want {
+			t.Errorf("capitalize(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
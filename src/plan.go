@@ -0,0 +1,189 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// PlanResult adalah satu rencana gabungan untuk beberapa target sekaligus
+type PlanResult struct {
+	// Steps terurut topologis: bahan selalu dibuat sebelum elemen yang memakainya
+	Steps []RecipeStep
+	// Trees berisi pohon resep tiap target, dibangun dari Steps yang sama
+	Trees []TreeNode
+	// Found bernilai false jika ada target yang tidak bisa dibuat
+	Found        bool
+	NodesVisited int
+	TruncatedBy  string
+}
+
+// planTargets mencari rencana dengan jumlah kombinasi berbeda paling sedikit untuk semua target.
+// Bahan yang dipakai beberapa target hanya dibuat sekali.
+func planTargets(ctx context.Context, graph *RecipeGraph, targets []string, opts SolverOptions) PlanResult {
+	budget := newSearchBudget(ctx, opts.Limits)
+	leaves := graph.newLeafSet(opts.Inventory)
+
+	var steps []RecipeStep
+	plans := shortestPlans(budget, graph, targets, 1, leaves)
+	found := len(plans) > 0
+	if found {
+		steps = plans[0]
+	} else if budget.Stopped() {
+		// Budget habis sebelum rencana optimal ditemukan, gabungkan pohon terdangkal tiap target
+		found = true
+		for _, t := range targets {
+			tree, ok := minDepthTree(budget, graph, canonicalName(t), leaves)
+			if !ok {
+				found = false
+				break
+			}
+			steps = append(steps, treeToSteps(tree)...)
+		}
+	}
+	if !found {
+		steps = nil
+	}
+	steps = orderSteps(steps, targets)
+
+	trees := make([]TreeNode, 0, len(targets))
+	for _, t := range targets {
+		trees = append(trees, buildTreeFromSteps(t, steps, graph.Elements))
+	}
+	return PlanResult{
+		Steps:        steps,
+		Trees:        trees,
+		Found:        found,
+		NodesVisited: budget.Nodes(),
+		TruncatedBy:  budget.TruncatedBy(),
+	}
+}
+
+// treeToSteps mengubah pohon resep menjadi daftar langkah (nama lowercase)
+func treeToSteps(tree TreeNode) []RecipeStep {
+	var steps []RecipeStep
+	var walk func(node TreeNode)
+	walk = func(node TreeNode) {
+		if len(node.Children) != 2 {
+			return
+		}
+		steps = append(steps, RecipeStep{
+			Element:     canonicalName(node.Name),
+			Ingredients: []string{canonicalName(node.Children[0].Name), canonicalName(node.Children[1].Name)},
+		})
+		walk(node.Children[0])
+		walk(node.Children[1])
+	}
+	walk(tree)
+	return steps
+}
+
+// orderSteps menghapus langkah ganda dan mengurutkannya secara topologis mulai dari roots.
+// Jika satu elemen punya beberapa langkah, yang pertama dipakai; langkah yang hanya dibutuhkan
+// resep lain elemen itu ikut dibuang, jadi setiap bahan hanya dibuat sekali.
+func orderSteps(steps []RecipeStep, roots []string) []RecipeStep {
+	byElement := make(map[string]RecipeStep, len(steps))
+	for _, s := range steps {
+		if _, ok := byElement[s.Element]; !ok {
+			byElement[s.Element] = s
+		}
+	}
+
+	ordered := make([]RecipeStep, 0, len(byElement))
+	done := make(map[string]bool, len(byElement))
+	var visit func(name string)
+	visit = func(name string) {
+		s, ok := byElement[name]
+		if !ok || done[name] {
+			return
+		}
+		done[name] = true
+		for _, ing := range s.Ingredients {
+			visit(ing)
+		}
+		ordered = append(ordered, s)
+	}
+	for _, r := range roots {
+		visit(canonicalName(r))
+	}
+	return ordered
+}
+
+// displaySteps mengembalikan salinan langkah dengan nama asli dari dataset
+func (g *RecipeGraph) displaySteps(steps []RecipeStep) []RecipeStep {
+	out := make([]RecipeStep, 0, len(steps))
+	for _, s := range steps {
+		ings := make([]string, len(s.Ingredients))
+		for i, ing := range s.Ingredients {
+			ings[i] = g.displayName(ing)
+		}
+		out = append(out, RecipeStep{Element: g.displayName(s.Element), Ingredients: ings})
+	}
+	return out
+}
+
+// normalizeTargets membuang target kosong dan duplikat lalu mengembalikan target yang tidak dikenal
+func (g *RecipeGraph) normalizeTargets(targets []string) (known, unknown []string) {
+	seen := make(map[string]bool)
+	for _, t := range targets {
+		t, ok := g.Resolve(t)
+		if t == "" || seen[t] {
+			continue
+		}
+		seen[t] = true
+		if ok {
+			known = append(known, t)
+		} else {
+			unknown = append(unknown, t)
+		}
+	}
+	return known, unknown
+}
+
+func planMessage(plan PlanResult, targets []string) string {
+	if !plan.Found {
+		return "No combined plan found"
+	}
+	return fmt.Sprintf("Combined plan for %d targets needs %d combinations", len(targets), len(plan.Steps))
+}
+
+func (g *RecipeGraph) displayNames(names []string) []string {
+	out := make([]string, len(names))
+	for i, n := range names {
+		out[i] = g.displayName(n)
+	}
+	return out
+}
+
+// CraftStep adalah satu baris urutan pembuatan, misalnya "1. Earth + Water → Mud"
+type CraftStep struct {
+	Step        int      `json:"step"`
+	Element     string   `json:"element"`
+	Ingredients []string `json:"ingredients"`
+	Text        string   `json:"text"`
+}
+
+// craftSequence mengubah langkah terurut menjadi daftar bernomor dengan nama asli dari dataset
+func (g *RecipeGraph) craftSequence(steps []RecipeStep) []CraftStep {
+	seq := make([]CraftStep, 0, len(steps))
+	for i, s := range g.displaySteps(steps) {
+		seq = append(seq, CraftStep{
+			Step:        i + 1,
+			Element:     s.Element,
+			Ingredients: s.Ingredients,
+			Text:        fmt.Sprintf("%d. %s → %s", i+1, strings.Join(s.Ingredients, " + "), s.Element),
+		})
+	}
+	return seq
+}
+
+// treeSequences meratakan setiap pohon menjadi urutan langkah tanpa duplikat. Subpohon yang
+// muncul berulang (hasil memoisasi buildRecipeTree) hanya dibuat sekali, sebelum langkah yang memakainya.
+func (g *RecipeGraph) treeSequences(trees []TreeNode) [][]CraftStep {
+	out := make([][]CraftStep, 0, len(trees))
+	for _, tree := range trees {
+		steps := orderSteps(treeToSteps(tree), []string{tree.Name})
+		out = append(out, g.craftSequence(steps))
+	}
+	return out
+}
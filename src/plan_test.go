@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+// TestTreeSequencesCraftsOnce memakai pohon yang membuat Stone dengan dua resep berbeda di dua
+// cabangnya, seperti yang bisa dihasilkan DFS. Stone tetap hanya dibuat sekali dengan resep pertama,
+// dan Mud yang hanya dibutuhkan resep kedua tidak ikut dibuat.
+func TestTreeSequencesCraftsOnce(t *testing.T) {
+	graph := newTestGraph(t, testElements)
+	leaf := func(name string) TreeNode { return TreeNode{Name: name} }
+	node := func(name string, a, b TreeNode) TreeNode { return TreeNode{Name: name, Children: []TreeNode{a, b}} }
+
+	lavaStone := node("Stone", node("Lava", leaf("Earth"), leaf("Fire")), leaf("Air"))
+	mudStone := node("Stone", node("Mud", leaf("Earth"), leaf("Water")), leaf("Fire"))
+
+	tests := []struct {
+		name string
+		tree TreeNode
+		want []string
+	}{
+		{
+			name: "different recipes",
+			tree: node("Wall", lavaStone, mudStone),
+			want: []string{
+				"1. Earth + Fire → Lava",
+				"2. Lava + Air → Stone",
+				"3. Stone + Stone → Wall",
+			},
+		},
+		{
+			// Resep yang muncul pertama yang dipakai, jadi giliran Lava yang dibuang
+			name: "different recipes reversed",
+			tree: node("Wall", mudStone, lavaStone),
+			want: []string{
+				"1. Earth + Water → Mud",
+				"2. Mud + Fire → Stone",
+				"3. Stone + Stone → Wall",
+			},
+		},
+		{
+			// Resep yang sama (bahan dibalik) tetap hanya dibuat sekali
+			name: "same recipe",
+			tree: node("Wall", lavaStone, node("Stone", leaf("Air"), node("Lava", leaf("Fire"), leaf("Earth")))),
+			want: []string{
+				"1. Earth + Fire → Lava",
+				"2. Lava + Air → Stone",
+				"3. Stone + Stone → Wall",
+			},
+		},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, s := range graph.treeSequences([]TreeNode{tt.tree})[0] {
+			got = append(got, s.Text)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s:\ngot  %q\nwant %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPlanTargets(t *testing.T) {
+	graph := newTestGraph(t, golemElements)
+	ctx := context.Background()
+
+	// Brick dan Clay sama-sama butuh Mud: Mud, Brick, Clay
+	plan := planTargets(ctx, graph, []string{"brick", "clay"}, SolverOptions{})
+	if !plan.Found || len(plan.Steps) != 3 {
+		t.Fatalf("brick+clay: found %v with steps %+v, want three steps", plan.Found, plan.Steps)
+	}
+	crafted := make(map[string]int)
+	for _, s := range plan.Steps {
+		crafted[s.Element]++
+	}
+	if crafted["mud"] != 1 {
+		t.Errorf("brick+clay: mud crafted %d times, want once", crafted["mud"])
+	}
+	if len(plan.Trees) != 2 || plan.Trees[0].Name != "Brick" || plan.Trees[1].Name != "Clay" {
+		t.Errorf("brick+clay: trees %+v, want one per target", plan.Trees)
+	}
+
+	// Golem paling murah tiga langkah (Mud, Clay, Golem) dan Wall tiga langkah (Mud, Brick, Wall);
+	// digabung Mud dibagi, jadi totalnya lima, bukan enam
+	plan = planTargets(ctx, graph, []string{"golem", "wall"}, SolverOptions{})
+	if !plan.Found || len(plan.Steps) != 5 {
+		t.Errorf("golem+wall: found %v with %d steps %+v, want 5", plan.Found, len(plan.Steps), plan.Steps)
+	}
+	for i, s := range plan.Steps {
+		for _, ing := range s.Ingredients {
+			if isBasicElement(ing) {
+				continue
+			}
+			if j := slices.IndexFunc(plan.Steps, func(p RecipeStep) bool { return p.Element == ing }); j < 0 || j > i {
+				t.Errorf("golem+wall: step %d (%s) uses %s before it is crafted", i, s.Element, ing)
+			}
+		}
+	}
+
+	tests := []struct {
+		name        string
+		targets     []string
+		limits      SearchLimits
+		wantFound   bool
+		truncatedBy string
+	}{
+		{"unknown target", []string{"golem", "unobtainium"}, SearchLimits{}, false, ""},
+		{"unknown target with exhausted budget", []string{"golem", "unobtainium"}, SearchLimits{MaxNodes: 1}, false, truncatedByMaxNodes},
+		// Budget habis sebelum rencana minimal ditemukan: setiap target tetap dapat pohon terdangkalnya
+		{"exhausted budget", []string{"golem", "wall"}, SearchLimits{MaxNodes: 1}, true, truncatedByMaxNodes},
+	}
+	for _, tt := range tests {
+		plan := planTargets(ctx, graph, tt.targets, SolverOptions{Limits: tt.limits})
+		if plan.Found != tt.wantFound || plan.TruncatedBy != tt.truncatedBy {
+			t.Errorf("%s: found %v truncatedBy %q, want %v %q", tt.name, plan.Found, plan.TruncatedBy, tt.wantFound, tt.truncatedBy)
+		}
+		if !plan.Found && len(plan.Steps) != 0 {
+			t.Errorf("%s: steps %+v without a plan", tt.name, plan.Steps)
+		}
+	}
+}
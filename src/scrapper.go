@@ -1,11 +1,15 @@
 package main
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
-	"net/http"
 	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -13,20 +17,73 @@ import (
 	"github.com/PuerkitoBio/goquery"
 )
 
-func Scraping() {
-	baseURL := "https://little-alchemy.fandom.com"
-	listURL := baseURL + "/wiki/Elements_(Little_Alchemy_2)"
-
-	fmt.Println("Starting Little Alchemy 2 recipe scraper...")
+// ScrapeOptions mengatur perintah scrape
+type ScrapeOptions struct {
+	// Game adalah nama di scrapeGames; OutFile kosong memakai output default game tersebut
+	Game        string
+	OutFile     string
+	CacheDir    string
+	Offline     bool
+	Concurrency int
+	Retries     int
+	// AllowPartial tetap menulis OutFile walaupun ada halaman yang gagal
+	AllowPartial bool
+	// Force tetap menulis OutFile walaupun validateElements menemukan error
+	Force bool
+	// Ignore adalah code validasi yang dibuang dari laporan, lihat ValidationReport.ignore
+	Ignore []string
+	// Previous adalah dataset lama untuk diff dan changelog; kosong berarti dataset yang dipakai server
+	// untuk game ini (Game.servedPath), previousNone berarti tanpa diff
+	Previous string
+	// AssetDir adalah folder tujuan gambar elemen; kosong berarti folder assets di samping OutFile
+	AssetDir string
+}
 
-	elementsList, err := getElementsList(listURL)
+// Scraping mengambil semua resep satu game dari wiki. Halaman disimpan di cache,
+// jadi jika ada yang gagal, menjalankan ulang perintah ini hanya mengunduh halaman yang belum ada.
+func Scraping(opts ScrapeOptions) error {
+	baseURL := "https://little-alchemy.fandom.com"
+	game, err := lookupGame(opts.Game)
 	if err != nil {
-		log.Fatalf("Failed to get elements list: %v", err)
+		return err
+	}
+	if opts.OutFile == "" {
+		opts.OutFile = game.OutFile
+	}
+	if opts.AssetDir == "" {
+		opts.AssetDir = filepath.Join(filepath.Dir(opts.OutFile), "assets")
+	}
+	// Dataset pembanding dicek sebelum scraping supaya kesalahan path tidak baru ketahuan di akhir
+	if opts.Previous == "" {
+		opts.Previous = game.servedPath()
+	}
+	if opts.Previous != previousNone {
+		if _, err := os.Stat(opts.Previous); err != nil {
+			return fmt.Errorf("no previous dataset to diff against: %w (use -previous to pick one, or -previous %s to skip the diff)", err, previousNone)
+		}
 	}
 
+	fetcher := newPageFetcher(opts.CacheDir, opts.Offline)
+	if opts.Retries >= 0 {
+		fetcher.Retries = opts.Retries
+	}
+	concurrency := max(opts.Concurrency, 1)
+
+	fmt.Printf("Starting %s recipe scraper...\n", game.Title)
+
+	var elementsList []string
 	seen := map[string]bool{}
-	for _, n := range elementsList {
-		seen[strings.ToLower(n)] = true
+	for _, page := range game.ListPages {
+		names, err := getElementsList(fetcher, baseURL+page)
+		if err != nil {
+			return fmt.Errorf("failed to get elements list: %w", err)
+		}
+		for _, n := range names {
+			if !seen[canonicalName(n)] {
+				elementsList = append(elementsList, n)
+				seen[canonicalName(n)] = true
+			}
+		}
 	}
 	for _, b := range basicElements {
 		if !seen[b] {
@@ -41,8 +98,10 @@ func Scraping() {
 	}
 
 	var elements []Element
+	var failed []string
+	cached := 0
 	var wg sync.WaitGroup
-	sem := make(chan struct{}, 3)
+	sem := make(chan struct{}, concurrency)
 	var mu sync.Mutex
 
 	for _, name := range elementsList {
@@ -53,58 +112,115 @@ func Scraping() {
 			defer func() { <-sem }()
 
 			url := baseURL + "/wiki/" + strings.ReplaceAll(name, " ", "_")
+			fromCache := fetcher.Cached(url)
 			fmt.Printf("Scraping: %s\n", name)
-			recs, err := scrapeRecipesLA2Only(url, name)
+			recs, meta, err := scrapeElementPage(fetcher, url, name, game)
 			if err != nil {
 				log.Printf("  error on %s: %v\n", name, err)
+				mu.Lock()
+				failed = append(failed, fmt.Sprintf("%s: %v", name, err))
+				mu.Unlock()
 				return
 			}
 
 			normalizedRecs := normalizeRecipes(recs)
+			fmt.Printf("Found %d %s recipes for %s\n", len(normalizedRecs), game.Title, name)
+
+			// Gambar yang gagal diunduh tidak menggagalkan halaman, elemen tetap memakai URL wiki-nya
+			if meta.Image != "" && opts.AssetDir != "" {
+				file := assetFileName(game.Name, name, meta.Image)
+				if err := fetcher.FetchAsset(meta.Image, filepath.Join(opts.AssetDir, file)); err != nil {
+					log.Printf("  image for %s: %v\n", name, err)
+				} else {
+					meta.ImageFile = file
+				}
+			}
 
 			mu.Lock()
 			elements = append(elements, Element{
-				Name:    name,
-				Recipes: normalizedRecs,
-				Tier:    -1,
+				Name:        name,
+				Recipes:     normalizedRecs,
+				Tier:        -1,
+				ElementMeta: meta,
 			})
+			if fromCache {
+				cached++
+			}
 			mu.Unlock()
-
-			time.Sleep(300 * time.Millisecond)
 		}(name)
 	}
 	wg.Wait()
 
-	elementsWithNoRecipes := 0
-	for _, e := range elements {
-		if len(e.Recipes) == 0 && !contains(basicElements, strings.ToLower(e.Name)) {
-			fmt.Printf("WARNING: %s has no recipes\n", e.Name)
-			elementsWithNoRecipes++
-		}
+	// Goroutine selesai dalam urutan acak, urutkan supaya hasil scraping selalu sama
+	sort.Slice(elements, func(i, j int) bool {
+		return canonicalName(elements[i].Name) < canonicalName(elements[j].Name)
+	})
+	sort.Strings(failed)
+	fmt.Printf("Scraped %d elements (%d from cache), %d failed\n", len(elements), cached, len(failed))
+	for _, f := range failed {
+		fmt.Printf("FAILED: %s\n", f)
+	}
+	if len(failed) > 0 && !opts.AllowPartial {
+		return fmt.Errorf("%d of %d pages failed, run scrape again to retry them (cache: %s)", len(failed), len(elementsList), opts.CacheDir)
 	}
-	fmt.Printf("Elements with no recipes: %d\n", elementsWithNoRecipes)
 
-	fmt.Println("Basic elements status:")
-	for _, b := range basicElements {
-		found := false
-		for _, e := range elements {
-			if strings.ToLower(e.Name) == strings.ToLower(b) {
-				found = true
-				break
-			}
-		}
-		fmt.Printf("%s: %v\n", b, found)
+	// Bahan resep ditulis dengan nama elemen yang sama persis, termasuk alias dari aliases.json
+	aliases, err := loadAliases(filepath.Dir(opts.OutFile))
+	if err != nil {
+		return err
 	}
+	elements = normalizeElements(elements, aliases)
+	recomputeTiers(elements)
 
-	calcTiersFix(elements)
+	// Laporan validasi selalu ditulis di samping output supaya masalahnya bisa diperiksa
+	report := validateElements(elements).ignore(opts.Ignore)
+	reportFile := strings.TrimSuffix(opts.OutFile, ".json") + ".validation.json"
+	if err := saveJSON(report, reportFile); err != nil {
+		return fmt.Errorf("failed saving %s: %w", reportFile, err)
+	}
+	fmt.Printf("Validation: %d errors, %d warnings, %d ignored (report in %s)\n", report.Errors, report.Warnings, report.Ignored, reportFile)
+	if !report.OK() && !opts.Force {
+		return fmt.Errorf("scraped data failed validation with %d errors, %s not written (see the report, accept known quirks with -ignore <code> or use -force to write it anyway)", report.Errors, opts.OutFile)
+	}
 
-	outFile := "elements.json"
-	if err := saveJSON(elements, outFile); err != nil {
-		log.Fatalf("Failed saving %s: %v", outFile, err)
+	if err := writeScrapeDiff(opts, elements); err != nil {
+		return err
+	}
+
+	if err := saveJSON(elements, opts.OutFile); err != nil {
+		return fmt.Errorf("failed saving %s: %w", opts.OutFile, err)
+	}
+	fmt.Printf("Done! Data with tiers in %s\n", opts.OutFile)
+	return nil
+}
+
+// previousNone sebagai -previous melewati diff, misalnya untuk scrape pertama sebuah game
+const previousNone = "none"
+
+// writeScrapeDiff membandingkan hasil scraping dengan opts.Previous dan menulis <out>.diff.json
+// serta <out>.changelog.md. Scraping sudah memastikan file itu ada, karena diff terhadap dataset
+// kosong akan melaporkan semua elemen sebagai elemen baru.
+func writeScrapeDiff(opts ScrapeOptions, elements []Element) error {
+	previous := opts.Previous
+	if previous == previousNone {
+		return nil
+	}
+	oldElements, err := readElements(previous)
+	if err != nil {
+		return fmt.Errorf("failed reading previous dataset: %w", err)
 	}
-	fmt.Printf("Done! Data with tiers in %s\n", outFile)
 
-	analyzeTiers(elements)
+	diff := diffDatasets(oldElements, elements)
+	base := strings.TrimSuffix(opts.OutFile, ".json")
+	if err := saveJSON(diff, base+".diff.json"); err != nil {
+		return fmt.Errorf("failed saving %s: %w", base+".diff.json", err)
+	}
+	title := "Dataset update " + time.Now().Format("2006-01-02")
+	if err := os.WriteFile(base+".changelog.md", []byte(diff.changelog(title)), 0644); err != nil {
+		return fmt.Errorf("failed saving %s: %w", base+".changelog.md", err)
+	}
+	fmt.Printf("Changes since %s: %s (changelog in %s)\n", previous, diff.summary(), base+".changelog.md")
+	return nil
 }
 
 func normalizeRecipes(recipes [][]string) [][]string {
@@ -114,8 +230,8 @@ func normalizeRecipes(recipes [][]string) [][]string {
 			a := strings.TrimSpace(recipe[0])
 			b := strings.TrimSpace(recipe[1])
 
-			a = cleanElementName(a)
-			b = cleanElementName(b)
+			a = cleanDisplayName(a)
+			b = cleanDisplayName(b)
 
 			if a != "" && b != "" {
 				normalized = append(normalized, []string{a, b})
@@ -125,26 +241,17 @@ func normalizeRecipes(recipes [][]string) [][]string {
 	return normalized
 }
 
-func cleanElementName(name string) string {
-	name = strings.Split(name, "(")[0]
-	name = strings.TrimSpace(name)
-	return name
-}
-
-func getElementsList(url string) ([]string, error) {
-	client := &http.Client{Timeout: 30 * time.Second}
-	req, _ := http.NewRequest("GET", url, nil)
-	req.Header.Set("User-Agent", "Mozilla/5.0")
-	res, err := client.Do(req)
+func getElementsList(fetcher *PageFetcher, url string) ([]string, error) {
+	body, err := fetcher.Fetch(url)
 	if err != nil {
 		return nil, err
 	}
-	defer res.Body.Close()
-	if res.StatusCode != 200 {
-		return nil, fmt.Errorf("status code: %d", res.StatusCode)
-	}
+	return parseElementsList(bytes.NewReader(body))
+}
 
-	doc, err := goquery.NewDocumentFromReader(res.Body)
+// parseElementsList membaca daftar nama elemen dari halaman "Elements (Little Alchemy 2)"
+func parseElementsList(r io.Reader) ([]string, error) {
+	doc, err := goquery.NewDocumentFromReader(r)
 	if err != nil {
 		return nil, err
 	}
@@ -161,7 +268,7 @@ func getElementsList(url string) ([]string, error) {
 	for _, sel := range selectors {
 		doc.Find(sel).Each(func(_ int, s *goquery.Selection) {
 			name := strings.TrimSpace(s.Text())
-			lc := strings.ToLower(name)
+			lc := canonicalName(name)
 			if name != "" && !strings.Contains(name, "Category:") && !seen[lc] {
 				elems = append(elems, name)
 				seen[lc] = true
@@ -174,21 +281,29 @@ func getElementsList(url string) ([]string, error) {
 	return elems, nil
 }
 
-func scrapeRecipesLA2Only(url string, targetElement string) ([][]string, error) {
-	client := &http.Client{Timeout: 30 * time.Second}
-	req, _ := http.NewRequest("GET", url, nil)
-	req.Header.Set("User-Agent", "Mozilla/5.0")
-	res, err := client.Do(req)
+// scrapeElementPage mengambil halaman elemen sekali lalu membaca resep dan metadatanya
+func scrapeElementPage(fetcher *PageFetcher, url string, targetElement string, game Game) ([][]string, ElementMeta, error) {
+	body, err := fetcher.Fetch(url)
 	if err != nil {
-		return nil, err
+		return nil, ElementMeta{}, err
 	}
-	defer res.Body.Close()
-
-	if res.StatusCode != 200 {
-		return nil, fmt.Errorf("status code: %d", res.StatusCode)
+	recipes, err := parseRecipes(bytes.NewReader(body), targetElement, game)
+	if err != nil {
+		return nil, ElementMeta{}, err
 	}
+	meta, err := parseElementMeta(bytes.NewReader(body), url)
+	return recipes, meta, err
+}
+
+// errNoRecipes berarti halaman punya bagian untuk game tetapi parser tidak menemukan resep di dalamnya,
+// biasanya karena layout wiki berubah. Lebih baik gagal daripada menulis daftar resep kosong.
+var errNoRecipes = errors.New("game section has no recipes the parser understands")
 
-	doc, err := goquery.NewDocumentFromReader(res.Body)
+// parseRecipes membaca resep targetElement untuk game dari HTML halaman wiki-nya tanpa menulis ke stdout;
+// laporan per halaman dicetak oleh Scraping. Bagian game lain dan "Used in" diabaikan. Jika bagian game ada tetapi tidak menghasilkan resep
+// (dan elemennya bukan elemen dasar), resep kosong dikembalikan bersama errNoRecipes.
+func parseRecipes(r io.Reader, targetElement string, game Game) ([][]string, error) {
+	doc, err := goquery.NewDocumentFromReader(r)
 	if err != nil {
 		return nil, err
 	}
@@ -196,72 +311,63 @@ func scrapeRecipesLA2Only(url string, targetElement string) ([][]string, error)
 	recipes := make([][]string, 0)
 	seen := make(map[string]bool)
 
-	inLA2Section := false
+	// Status bagian dihitung sambil berjalan urut dokumen, jadi bagian "Used in" atau
+	// game lain di bawah halaman tidak mematikan bagian game yang dicari di atasnya.
+	inSection := false
+	foundSection := false
 	inUsedInSection := false
-
-	doc.Find("h1, h2, h3, h4").Each(func(_ int, h *goquery.Selection) {
-		headerText := strings.ToLower(strings.TrimSpace(h.Text()))
-
-		if strings.Contains(headerText, "little alchemy 2") {
-			inLA2Section = true
-			inUsedInSection = false
-			fmt.Printf("Found Little Alchemy 2 section for: %s\n", targetElement)
-		} else if strings.Contains(headerText, "little alchemy") && !strings.Contains(headerText, "2") {
-			inLA2Section = false
-			fmt.Printf("Found Little Alchemy 1 section for: %s (ignoring)\n", targetElement)
-		} else if strings.Contains(headerText, "used in") {
-			inUsedInSection = true
-			fmt.Printf("Found 'Used in' section for: %s (ignoring)\n", targetElement)
-		} else {
-			inLA2Section = false
-			inUsedInSection = false
-		}
-	})
+	sectionLevel := 0
+	var paragraphs []*goquery.Selection
 
 	doc.Find("div.mw-parser-output > *").Each(func(_ int, s *goquery.Selection) {
-		if inLA2Section && !inUsedInSection {
-			if s.Is("table") {
-				s.Find("tr").Each(func(_ int, row *goquery.Selection) {
-					cols := row.Find("td")
-					if cols.Length() >= 3 {
-						result := strings.TrimSpace(cols.Eq(2).Text())
-						if strings.EqualFold(result, targetElement) {
-							a := strings.TrimSpace(cols.Eq(0).Text())
-							b := strings.TrimSpace(cols.Eq(1).Text())
-							addRecipe(&recipes, seen, a, b)
-						}
-					}
-				})
-			}
-
-			if s.Is("ul") {
-				s.Find("li").Each(func(_ int, li *goquery.Selection) {
-					text := li.Text()
-					if strings.Contains(text, "+") || strings.Contains(text, "→") || strings.Contains(text, "=") {
-						parseRecipeFromText(&recipes, seen, text, targetElement)
-					}
-				})
+		if level := headerLevel(s); level > 0 {
+			headerText := strings.ToLower(strings.TrimSpace(s.Text()))
+
+			if section := headerGame(headerText); game.covers(section) {
+				inSection = true
+				foundSection = true
+				inUsedInSection = false
+				sectionLevel = level
+			} else if section != "" {
+				inSection = false
+			} else if strings.Contains(headerText, "used in") {
+				inUsedInSection = true
+			} else {
+				// Subjudul seperti "Recipes" tetap bagian dari game di atasnya
+				inSection = inSection && level > sectionLevel
+				inUsedInSection = false
 			}
+			return
 		}
-	})
 
-	doc.Find("div.mw-parser-output > *").Each(func(i int, s *goquery.Selection) {
-		var inLA2Context bool
+		if inUsedInSection {
+			return
+		}
+		if s.Is("p") {
+			paragraphs = append(paragraphs, s)
+		}
+		if !inSection {
+			return
+		}
 
-		prev := s.Prev()
-		for prev.Length() > 0 {
-			if prev.Is("h1, h2, h3, h4") {
-				headerText := strings.ToLower(strings.TrimSpace(prev.Text()))
-				inLA2Context = strings.Contains(headerText, "little alchemy 2")
-				break
-			}
-			prev = prev.Prev()
+		if s.Is("table") {
+			s.Find("tr").Each(func(_ int, row *goquery.Selection) {
+				cols := row.Find("td")
+				if cols.Length() >= 3 {
+					result := strings.TrimSpace(cols.Eq(2).Text())
+					if canonicalName(result) == canonicalName(targetElement) {
+						a := strings.TrimSpace(cols.Eq(0).Text())
+						b := strings.TrimSpace(cols.Eq(1).Text())
+						addRecipe(&recipes, seen, a, b)
+					}
+				}
+			})
 		}
 
-		if inLA2Context && s.Is("ul") && !inUsedInSection {
+		if s.Is("ul") {
 			s.Find("li").Each(func(_ int, li *goquery.Selection) {
 				text := li.Text()
-				if strings.Contains(text, "→") || strings.Contains(text, "=") || strings.Contains(text, "+") {
+				if strings.Contains(text, "+") || strings.Contains(text, "→") || strings.Contains(text, "=") {
 					parseRecipeFromText(&recipes, seen, text, targetElement)
 				}
 			})
@@ -269,48 +375,59 @@ func scrapeRecipesLA2Only(url string, targetElement string) ([][]string, error)
 	})
 
 	if len(recipes) == 0 {
-		inLA2Context := false
+		inGameContext := false
 
-		doc.Find("div.mw-parser-output p").Each(func(_ int, p *goquery.Selection) {
+		for _, p := range paragraphs {
 			text := strings.ToLower(p.Text())
 
-			if strings.Contains(text, "little alchemy 2") {
-				inLA2Context = true
+			if game.covers(headerGame(text)) {
+				inGameContext = true
 			}
 
-			if inLA2Context && !inUsedInSection &&
+			if inGameContext &&
 				(strings.Contains(text, "recipe") || strings.Contains(text, "combine") ||
 					strings.Contains(text, "make") || strings.Contains(text, "create")) {
 				parseRecipeFromText(&recipes, seen, text, targetElement)
 			}
-		})
+		}
 	}
 
 	if len(recipes) == 0 {
-		isLA2Page := false
+		isGamePage := false
 
 		doc.Find("title, h1.page-header__title").Each(func(_ int, title *goquery.Selection) {
-			if strings.Contains(strings.ToLower(title.Text()), "little alchemy 2") {
-				isLA2Page = true
+			if game.covers(headerGame(strings.ToLower(title.Text()))) {
+				isGamePage = true
 			}
 		})
 
-		if isLA2Page {
-			fallbackRecipes := fallbackScrape(doc, targetElement)
+		if isGamePage {
+			fallbackRecipes := fallbackScrape(doc, targetElement, game)
 			for _, recipe := range fallbackRecipes {
 				addRecipe(&recipes, seen, recipe[0], recipe[1])
 			}
 		}
 	}
 
-	fmt.Printf("Found %d Little Alchemy 2 recipes for %s\n", len(recipes), targetElement)
-
+	if foundSection && len(recipes) == 0 && !isBasicElement(targetElement) {
+		return recipes, fmt.Errorf("%w: %s section of %s", errNoRecipes, game.Title, targetElement)
+	}
 	return recipes, nil
 }
 
+// headerLevel mengembalikan 2 untuk <h2> dan seterusnya, atau 0 jika s bukan judul
+func headerLevel(s *goquery.Selection) int {
+	for level, tag := range []string{"h1", "h2", "h3", "h4"} {
+		if s.Is(tag) {
+			return level + 1
+		}
+	}
+	return 0
+}
+
 func addRecipe(recipes *[][]string, seen map[string]bool, a, b string) {
-	a = cleanElementName(a)
-	b = cleanElementName(b)
+	a = cleanDisplayName(a)
+	b = cleanDisplayName(b)
 	if a == "" || b == "" {
 		return
 	}
@@ -319,7 +436,7 @@ func addRecipe(recipes *[][]string, seen map[string]bool, a, b string) {
 		a, b = b, a
 	}
 
-	key := strings.ToLower(a + "|" + b)
+	key := canonicalName(a) + "|" + canonicalName(b)
 	if !seen[key] {
 		*recipes = append(*recipes, []string{a, b})
 		seen[key] = true
@@ -345,7 +462,7 @@ func parseRecipeFromText(recipes *[][]string, seen map[string]bool, text, target
 	}
 
 	result := strings.TrimSpace(parts[1])
-	if !strings.EqualFold(result, targetElement) {
+	if canonicalName(result) != canonicalName(targetElement) {
 		return
 	}
 
@@ -357,7 +474,7 @@ func parseRecipeFromText(recipes *[][]string, seen map[string]bool, text, target
 	}
 }
 
-func fallbackScrape(doc *goquery.Document, targetElement string) [][]string {
+func fallbackScrape(doc *goquery.Document, targetElement string, game Game) [][]string {
 	recipes := make([][]string, 0)
 	seen := make(map[string]bool)
 
@@ -371,8 +488,7 @@ func fallbackScrape(doc *goquery.Document, targetElement string) [][]string {
 			sectionHeader := strings.ToLower(strings.TrimSpace(prev.Text()))
 			if strings.Contains(sectionHeader, "used in") {
 				inUsedSection = true
-			} else if strings.Contains(sectionHeader, "little alchemy") &&
-				!strings.Contains(sectionHeader, "little alchemy 2") {
+			} else if section := headerGame(sectionHeader); section != "" && !game.covers(section) {
 				inUsedSection = true
 			}
 		}
@@ -382,10 +498,10 @@ func fallbackScrape(doc *goquery.Document, targetElement string) [][]string {
 				cols := row.Find("td")
 				if cols.Length() >= 3 {
 					result := strings.TrimSpace(cols.Eq(2).Text())
-					if strings.EqualFold(result, targetElement) {
+					if canonicalName(result) == canonicalName(targetElement) {
 						a := strings.TrimSpace(cols.Eq(0).Text())
 						b := strings.TrimSpace(cols.Eq(1).Text())
-						key := strings.ToLower(a + "|" + b)
+						key := canonicalName(a) + "|" + canonicalName(b)
 						if a != "" && b != "" && !seen[key] {
 							recipes = append(recipes, []string{a, b})
 							seen[key] = true
@@ -399,211 +515,18 @@ func fallbackScrape(doc *goquery.Document, targetElement string) [][]string {
 	return recipes
 }
 
-func calcTiersFix(elements []Element) {
-
-	// Step 1: Create required mappings
-	elementMap := make(map[string]*Element)      
-	elementRecipes := make(map[string][][]string)
-
-	for i := range elements {
-		lowerName := strings.ToLower(elements[i].Name)
-		elementMap[lowerName] = &elements[i]
-
-		var normalizedRecipes [][]string
-		for _, recipe := range elements[i].Recipes {
-			if len(recipe) == 2 {
-				a := strings.ToLower(recipe[0])
-				b := strings.ToLower(recipe[1])
-				normalizedRecipes = append(normalizedRecipes, []string{a, b})
-			}
-		}
-		elementRecipes[lowerName] = normalizedRecipes
-	}
-
-	for _, basic := range basicElements {
-		if elem, exists := elementMap[basic]; exists {
-			elem.Tier = 0
-		} else {
-			fmt.Printf("ERROR: Basic element %s missing from map\n", basic)
-		}
-	}
-
-	dependencies := make(map[string]map[string]bool)
-	for name, recipes := range elementRecipes {
-		deps := make(map[string]bool)
-		for _, recipe := range recipes {
-			if len(recipe) == 2 {
-				deps[recipe[0]] = true
-				deps[recipe[1]] = true
-			}
-		}
-		dependencies[name] = deps
-	}
-
-	changed := true
-	iteration := 0
-	maxIterations := 100
-
-	for changed && iteration < maxIterations {
-		changed = false
-		iteration++
-
-		for elemName, elem := range elementMap {
-			if elem.Tier != -1 || len(elementRecipes[elemName]) == 0 {
-				continue
-			}
-
-			for _, recipe := range elementRecipes[elemName] {
-				if len(recipe) != 2 {
-					continue
-				}
-
-				maxIngredientTier := -1
-				allIngredientsHaveTier := true
-
-				for _, ingredient := range recipe {
-					if ingElem, exists := elementMap[ingredient]; exists && ingElem.Tier != -1 {
-						if ingElem.Tier > maxIngredientTier {
-							maxIngredientTier = ingElem.Tier
-						}
-					} else {
-						allIngredientsHaveTier = false
-						break
-					}
-				}
-
-				if allIngredientsHaveTier {
-					elem.Tier = maxIngredientTier + 1
-					changed = true
-					break
-				}
-			}
-		}
-
-		fmt.Printf("Iteration %d: Updated %d elements\n", iteration, countUpdatedElements(elements))
-	}
-
-	missingRecipesCount := 0
-	for _, elem := range elements {
-		if elem.Tier == -1 && len(elem.Recipes) == 0 && !isBasicElement(elem.Name) {
-			elem.Tier = 999
-			missingRecipesCount++
-		}
-	}
-
-	unresolvableCount := 0
-	for _, elem := range elements {
-		if elem.Tier == -1 {
-			elem.Tier = 998
-			unresolvableCount++
-		}
-	}
-
-	fmt.Printf("\nTier calculation completed in %d iterations\n", iteration)
-	fmt.Printf("Elements with missing recipes: %d\n", missingRecipesCount)
-	fmt.Printf("Elements with unresolvable tiers: %d\n", unresolvableCount)
-}
-
-func countUpdatedElements(elements []Element) int {
-	count := 0
-	for _, e := range elements {
-		if e.Tier != -1 {
-			count++
-		}
-	}
-	return count
-}
-
-func analyzeTiers(elements []Element) {
-	tierCounts := make(map[int]int)
-	for _, elem := range elements {
-		tierCounts[elem.Tier]++
-	}
-
-	fmt.Println("\n--- Tier Distribution Analysis ---")
-	fmt.Printf("Total elements: %d\n", len(elements))
-	fmt.Println("Tier distribution:")
-
-	totalNormalTiers := 0
-	for t := 0; t <= 20; t++ {
-		if count, ok := tierCounts[t]; ok && count > 0 {
-			fmt.Printf("  Tier %d: %d elements\n", t, count)
-			totalNormalTiers += count
-		}
-	}
-
-	if count, ok := tierCounts[-1]; ok && count > 0 {
-		fmt.Printf("  Tier -1 (unprocessed): %d elements\n", count)
-	}
-	if count, ok := tierCounts[998]; ok && count > 0 {
-		fmt.Printf("  Tier 998 (unresolvable dependencies): %d elements\n", count)
-	}
-	if count, ok := tierCounts[999]; ok && count > 0 {
-		fmt.Printf("  Tier 999 (no recipes): %d elements\n", count)
-	}
-
-	fmt.Printf("\nElements with normal tiers (0-20): %d (%.1f%%)\n",
-		totalNormalTiers, float64(totalNormalTiers)/float64(len(elements))*100)
-
-	categories := []struct {
-		name string
-		tier int
-		max  int
-	}{
-		{"Normal tier 1", 1, 5},
-		{"Normal tier 2", 2, 5},
-		{"Normal tier 3", 3, 5},
-		{"Unresolvable dependencies (998)", 998, 5},
-		{"No recipes (999)", 999, 5},
-	}
-
-	for _, cat := range categories {
-		if tierCounts[cat.tier] > 0 {
-			fmt.Printf("\nSample of %s elements:\n", cat.name)
-			count := 0
-			for _, elem := range elements {
-				if elem.Tier == cat.tier {
-					recipeStr := "no recipes"
-					if len(elem.Recipes) > 0 {
-						ingredients := elem.Recipes[0]
-						if len(ingredients) >= 2 {
-							recipeStr = fmt.Sprintf("%s + %s", ingredients[0], ingredients[1])
-						}
-					}
-					fmt.Printf("  - %s (%s)\n", elem.Name, recipeStr)
-					count++
-					if count >= cat.max {
-						break
-					}
-				}
-			}
-		}
-	}
-
-	fmt.Println("\nAll Tier 1 elements:")
-	for _, elem := range elements {
-		if elem.Tier == 1 {
-			recipeStr := "no recipes"
-			if len(elem.Recipes) > 0 {
-				ingredients := elem.Recipes[0]
-				if len(ingredients) >= 2 {
-					recipeStr = fmt.Sprintf("%s + %s", ingredients[0], ingredients[1])
-				}
-			}
-			fmt.Printf("  - %s (%s)\n", elem.Name, recipeStr)
-		}
-	}
-}
-
-func saveJSON(elements []Element, file string) error {
-	f, err := os.Create(file)
+// saveJSON menulis ke file sementara di folder yang sama lalu me-rename-nya ke file tujuan.
+// DatasetStore.Watch bisa membaca file tujuan kapan saja, jadi file itu tidak boleh setengah tertulis.
+func saveJSON(v interface{}, file string) error {
+	data, err := json.MarshalIndent(v, "", "  ")
 	if err != nil {
 		return err
 	}
-	defer f.Close()
-	enc := json.NewEncoder(f)
-	enc.SetIndent("", "  ")
-	return enc.Encode(elements)
+	tmp := file + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, file)
 }
 
 func min(a, b int) int {
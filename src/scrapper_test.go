@@ -0,0 +1,147 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Jalankan "go test -run 'TestParseRecipes|TestParseElementMetaGolden' -update" untuk menulis ulang file golden
+// setelah parser sengaja diubah. Periksa diff-nya sebelum commit.
+var update = flag.Bool("update", false, "rewrite testdata/parser/*.golden.json")
+
+// parserTarget menurunkan nama elemen dari nama file fixture, misalnya steam_engine.html -> "Steam engine"
+func parserTarget(path string) string {
+	name := strings.TrimSuffix(filepath.Base(path), ".html")
+	name = strings.ReplaceAll(name, "_", " ")
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+// TestParseRecipes menjalankan parser untuk setiap game pada setiap halaman di testdata/parser
+// dan membandingkan hasilnya dengan <nama>.<game>.golden.json. Halaman asli dari scrape-cache bisa
+// disalin ke sini (dengan nama file sesuai elemennya) untuk menambah kasus baru.
+func TestParseRecipes(t *testing.T) {
+	pages, err := filepath.Glob(filepath.Join("testdata", "parser", "*.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pages) == 0 {
+		t.Fatal("no fixtures in testdata/parser")
+	}
+
+	for _, page := range pages {
+		for _, game := range scrapeGames {
+			testParseRecipesGolden(t, page, game)
+		}
+	}
+}
+
+// parseGolden adalah isi file golden: resep yang ditemukan, atau error jika parser menolak halaman
+type parseGolden struct {
+	Recipes [][]string `json:"recipes"`
+	Error   string     `json:"error,omitempty"`
+}
+
+func testParseRecipesGolden(t *testing.T, page string, game Game) {
+	target := parserTarget(page)
+	t.Run(target+"/"+game.Name, func(t *testing.T) {
+		f, err := os.Open(page)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+
+		recipes, err := parseRecipes(f, target, game)
+		got := parseGolden{Recipes: recipes}
+		if err != nil {
+			got.Error = err.Error()
+		}
+
+		golden := strings.TrimSuffix(page, ".html") + "." + game.Name + ".golden.json"
+		if *update {
+			data, err := json.MarshalIndent(got, "", "  ")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(golden, append(data, '\n'), 0644); err != nil {
+				t.Fatal(err)
+			}
+			return
+		}
+
+		data, err := os.ReadFile(golden)
+		if err != nil {
+			t.Fatalf("%v (run with -update to create it)", err)
+		}
+		var want parseGolden
+		if err := json.Unmarshal(data, &want); err != nil {
+			t.Fatalf("%s: %v", golden, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s recipes for %s changed\n got: %+v\nwant: %+v", game.Name, target, got, want)
+		}
+	})
+}
+
+func TestParseElementsList(t *testing.T) {
+	f, err := os.Open(filepath.Join("testdata", "elements_list.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	got, err := parseElementsList(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"Air", "Alcohol", "Steam engine", "Mud"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestParseRecipeFromText(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		target string
+		want   [][]string
+	}{
+		{"plus only", "Water + Earth", "Mud", [][]string{{"Earth", "Water"}}},
+		{"arrow", "Mud + Fire → Brick", "Brick", [][]string{{"Fire", "Mud"}}},
+		{"equals", " Boiler + Wheel = Steam engine ", "steam engine", [][]string{{"Boiler", "Wheel"}}},
+		{"other result", "Brick + Brick → Wall", "Brick", nil},
+		{"three ingredients", "Fire + Earth + Water", "Mud", nil},
+		{"no operator", "Mud is wet earth", "Mud", nil},
+		{"disambiguation", "Steam (Little Alchemy 2) + Machine = Steam engine", "Steam engine", [][]string{{"Machine", "Steam"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got [][]string
+			parseRecipeFromText(&got, map[string]bool{}, tt.text, tt.target)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddRecipeDedup(t *testing.T) {
+	var recipes [][]string
+	seen := map[string]bool{}
+	addRecipe(&recipes, seen, "Water", "Earth")
+	addRecipe(&recipes, seen, "earth", "water")
+	addRecipe(&recipes, seen, "Earth (Little Alchemy 2)", "Water")
+	addRecipe(&recipes, seen, "", "Water")
+	addRecipe(&recipes, seen, "Fire", "Fire")
+
+	want := [][]string{{"Earth", "Water"}, {"Fire", "Fire"}}
+	if !reflect.DeepEqual(recipes, want) {
+		t.Errorf("got %v, want %v", recipes, want)
+	}
+}
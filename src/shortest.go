@@ -0,0 +1,283 @@
+package main
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gorilla/websocket"
+)
+
+// Objective untuk solver SHORTEST
+const (
+	objectiveSteps = "steps" // jumlah kombinasi berbeda paling sedikit
+	objectiveDepth = "depth" // kedalaman pohon paling kecil, selalu satu pohon berapa pun MaxRecipes
+)
+
+// parseObjective memeriksa objective dari request. Kosong berarti objectiveSteps; nilai lain ditolak
+// supaya salah ketik tidak diam-diam dicari sebagai steps.
+func parseObjective(s string) (string, error) {
+	switch objective := strings.ToLower(strings.TrimSpace(s)); objective {
+	case "", objectiveSteps, objectiveDepth:
+		return objective, nil
+	default:
+		return "", fmt.Errorf("unknown objective %q (available: %s, %s)", s, objectiveSteps, objectiveDepth)
+	}
+}
+
+// shortestState adalah himpunan elemen yang masih harus dibuat.
+//
+// Elemen diselesaikan dari tier tertinggi ke terendah. Karena bahan selalu bertier lebih
+// rendah dari hasilnya, elemen yang sudah dibuat tidak akan pernah dibutuhkan lagi,
+// sehingga biaya sisa hanya bergantung pada himpunan open. Setiap elemen open butuh
+// minimal satu kombinasi, jadi len(open) adalah heuristik A* yang admissible.
+type shortestState struct {
+	open   []string // terurut berdasarkan tier menurun, lalu nama
+	cost   int
+	step   RecipeStep
+	parent *shortestState
+	index  int
+}
+
+func (s *shortestState) priority() int {
+	return s.cost + len(s.open)
+}
+
+func (s *shortestState) key() string {
+	return strings.Join(s.open, "|")
+}
+
+func (s *shortestState) steps() []RecipeStep {
+	var steps []RecipeStep
+	for curr := s; curr.parent != nil; curr = curr.parent {
+		steps = append(steps, curr.step)
+	}
+	return steps
+}
+
+type shortestQueue []*shortestState
+
+func (q shortestQueue) Len() int { return len(q) }
+func (q shortestQueue) Less(i, j int) bool {
+	if q[i].priority() != q[j].priority() {
+		return q[i].priority() < q[j].priority()
+	}
+	if len(q[i].open) != len(q[j].open) {
+		return len(q[i].open) < len(q[j].open)
+	}
+	return q[i].key() < q[j].key()
+}
+func (q shortestQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+func (q *shortestQueue) Push(x interface{}) {
+	s := x.(*shortestState)
+	s.index = len(*q)
+	*q = append(*q, s)
+}
+func (q *shortestQueue) Pop() interface{} {
+	old := *q
+	s := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return s
+}
+
+// validRecipes mengembalikan resep elemen yang bahannya ada dan bertier lebih rendah
+// (atau sudah dimiliki, karena elemen di leaves tidak pernah dibuat ulang)
+func validRecipes(graph *RecipeGraph, name string, leaves LeafSet) [][2]string {
+	tier := graph.Tiers[name]
+	var recipes [][2]string
+	for _, recipe := range graph.Recipes[name] {
+		elemA, okA := graph.Elements[recipe[0]]
+		elemB, okB := graph.Elements[recipe[1]]
+		if !okA || !okB || (elemA.Tier >= tier && !leaves[recipe[0]]) || (elemB.Tier >= tier && !leaves[recipe[1]]) {
+			continue
+		}
+		recipes = append(recipes, recipe)
+	}
+	return recipes
+}
+
+// sortOpen mengurutkan elemen open dari tier tertinggi supaya yang pertama selalu aman diselesaikan
+func sortOpen(graph *RecipeGraph, open []string) {
+	sort.Slice(open, func(i, j int) bool {
+		ti, tj := graph.Tiers[open[i]], graph.Tiers[open[j]]
+		if ti != tj {
+			return ti > tj
+		}
+		return open[i] < open[j]
+	})
+}
+
+// shortestPlans mencari maxRecipes rencana dengan jumlah kombinasi berbeda paling sedikit,
+// terurut dari yang terbaik. Target boleh lebih dari satu; bahan yang sama hanya dihitung sekali.
+func shortestPlans(budget *SearchBudget, graph *RecipeGraph, targets []string, maxRecipes int, leaves LeafSet) [][]RecipeStep {
+	if maxRecipes <= 0 {
+		maxRecipes = 1
+	}
+
+	start := &shortestState{}
+	seen := make(map[string]bool)
+	for _, t := range targets {
+		t = canonicalName(t)
+		if !leaves[t] && !seen[t] {
+			seen[t] = true
+			start.open = append(start.open, t)
+		}
+	}
+	sortOpen(graph, start.open)
+
+	queue := &shortestQueue{}
+	heap.Push(queue, start)
+	// Setiap state boleh diambil maxRecipes kali supaya rencana alternatif tidak hilang
+	// saat dua jalur berbeda bertemu di himpunan open yang sama (k-shortest paths)
+	popped := make(map[string]int)
+
+	var plans [][]RecipeStep
+	for queue.Len() > 0 && len(plans) < maxRecipes {
+		curr := heap.Pop(queue).(*shortestState)
+		if !budget.Visit() {
+			break
+		}
+
+		// Urutan penyelesaian selalu sama, jadi setiap state akhir mewakili rencana yang berbeda
+		if len(curr.open) == 0 {
+			plans = append(plans, curr.steps())
+			continue
+		}
+
+		key := curr.key()
+		if popped[key] >= maxRecipes {
+			continue
+		}
+		popped[key]++
+
+		elem := curr.open[0]
+		rest := curr.open[1:]
+		for _, recipe := range validRecipes(graph, elem, leaves) {
+			next := &shortestState{
+				open:   append([]string{}, rest...),
+				cost:   curr.cost + 1,
+				step:   RecipeStep{Element: elem, Ingredients: []string{recipe[0], recipe[1]}},
+				parent: curr,
+			}
+			for _, ing := range recipe {
+				if !leaves[ing] && !contains(next.open, ing) {
+					next.open = append(next.open, ing)
+				}
+			}
+			sortOpen(graph, next.open)
+			if len(next.open) == 0 || popped[next.key()] < maxRecipes {
+				heap.Push(queue, next)
+			}
+		}
+	}
+	return plans
+}
+
+// minDepthTree membangun pohon dengan kedalaman paling kecil lewat dynamic programming.
+// Jika beberapa resep sama dalamnya, dipilih yang jumlah kombinasinya paling sedikit.
+func minDepthTree(budget *SearchBudget, graph *RecipeGraph, target string, leaves LeafSet) (TreeNode, bool) {
+	type best struct {
+		depth, size int
+		recipe      [2]string
+		ok          bool
+	}
+	memo := make(map[string]best)
+
+	var solve func(name string) best
+	solve = func(name string) best {
+		if b, found := memo[name]; found {
+			return b
+		}
+		if leaves[name] {
+			memo[name] = best{ok: true}
+			return memo[name]
+		}
+		// Tandai sebagai gagal dulu untuk memutus siklus pada data yang tiernya rusak
+		memo[name] = best{}
+		budget.Visit()
+
+		var result best
+		for _, recipe := range validRecipes(graph, name, leaves) {
+			a, b := solve(recipe[0]), solve(recipe[1])
+			if !a.ok || !b.ok {
+				continue
+			}
+			cand := best{depth: max(a.depth, b.depth) + 1, size: a.size + b.size + 1, recipe: recipe, ok: true}
+			if !result.ok || cand.depth < result.depth || (cand.depth == result.depth && cand.size < result.size) {
+				result = cand
+			}
+		}
+		memo[name] = result
+		return result
+	}
+
+	if !solve(target).ok {
+		return TreeNode{}, false
+	}
+
+	var build func(name string) TreeNode
+	build = func(name string) TreeNode {
+		node := TreeNode{Name: graph.displayName(name)}
+		if b := memo[name]; b.ok && !leaves[name] {
+			node.Children = []TreeNode{build(b.recipe[0]), build(b.recipe[1])}
+		}
+		return node
+	}
+	tree := build(target)
+	sortTreeChildren(&tree)
+	return tree, true
+}
+
+func shortestMultiple(budget *SearchBudget, graph *RecipeGraph, target string, maxRecipes int, objective string, leaves LeafSet) []TreeNode {
+	target = canonicalName(target)
+	if leaves[target] {
+		return []TreeNode{{Name: graph.displayName(target)}}
+	}
+	if _, ok := graph.Elements[target]; !ok {
+		return []TreeNode{}
+	}
+
+	if objective != objectiveDepth {
+		plans := shortestPlans(budget, graph, []string{target}, maxRecipes, leaves)
+		if len(plans) > 0 || !budget.Stopped() {
+			trees := make([]TreeNode, 0, len(plans))
+			for _, steps := range plans {
+				trees = append(trees, buildTreeFromSteps(target, steps, graph.Elements))
+			}
+			return trees
+		}
+		// Budget habis sebelum ada rencana optimal, pakai pohon terdangkal sebagai gantinya
+	}
+
+	// Pohon terdangkal tetap dihitung walau budget habis karena biayanya linear terhadap jumlah elemen
+	if tree, ok := minDepthTree(budget, graph, target, leaves); ok {
+		return []TreeNode{tree}
+	}
+	return []TreeNode{}
+}
+
+type shortestSolver struct{}
+
+func (shortestSolver) Name() string  { return "SHORTEST" }
+func (shortestSolver) Label() string { return "Shortest Recipe Search" }
+
+func (shortestSolver) Solve(ctx context.Context, graph *RecipeGraph, target string, opts SolverOptions) SearchResult {
+	budget := newSearchBudget(ctx, opts.Limits)
+	trees := shortestMultiple(budget, graph, target, opts.MaxRecipes, opts.Objective, graph.newLeafSet(opts.Inventory))
+	return budget.result(trees)
+}
+
+// Solver ini cukup cepat sehingga tidak punya mode live
+func (s shortestSolver) SolveLive(ctx context.Context, graph *RecipeGraph, target string, opts SolverOptions, conn *websocket.Conn) SearchResult {
+	return s.Solve(ctx, graph, target, opts)
+}
+
+func init() {
+	registerSolver(shortestSolver{})
+}
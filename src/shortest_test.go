@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// golemElements: resep pertama Golem butuh lima kombinasi berbeda, resep terakhir hanya tiga
+// karena Clay dipakai dua kali. Semua pohon Golem sedalam tier-nya (3).
+var golemElements = append(append([]Element{}, testElements...),
+	Element{Name: "Brittle", Recipes: [][]string{{"Mud", "Fire"}}},
+	Element{Name: "Obsidian", Recipes: [][]string{{"Lava", "Air"}}},
+	Element{Name: "Clay", Recipes: [][]string{{"Mud", "Air"}}},
+	Element{Name: "Silt", Recipes: [][]string{{"Mud", "Earth"}}},
+	Element{Name: "Golem", Recipes: [][]string{{"Brittle", "Obsidian"}, {"Clay", "Silt"}, {"Clay", "Clay"}}},
+)
+
+// distinctSteps menghitung kombinasi berbeda di pohon; kombinasi yang sama hanya dihitung sekali
+func distinctSteps(tree TreeNode) int {
+	seen := make(map[string]bool)
+	for _, s := range treeToSteps(tree) {
+		ings := append([]string{}, s.Ingredients...)
+		sort.Strings(ings)
+		seen[s.Element+"="+strings.Join(ings, "+")] = true
+	}
+	return len(seen)
+}
+
+func treeDepth(tree TreeNode) int {
+	depth := 0
+	for _, c := range tree.Children {
+		depth = max(depth, treeDepth(c)+1)
+	}
+	return depth
+}
+
+func TestShortestIsMinimal(t *testing.T) {
+	graph := newTestGraph(t, golemElements)
+	ctx := context.Background()
+
+	// Fixture ini hanya berguna jika DFS tidak kebetulan menemukan yang optimal lebih dulu.
+	// DFS mengikuti urutan resep di dataset, jadi pohon pertamanya memakai Brittle + Obsidian.
+	dfs, _ := lookupSolver("DFS")
+	first := dfs.Solve(ctx, graph, "golem", SolverOptions{MaxRecipes: 1, Deterministic: true})
+	if len(first.Trees) == 0 || distinctSteps(first.Trees[0]) != 5 {
+		t.Fatalf("DFS first tree = %+v, want the five-step Brittle + Obsidian plan", first.Trees)
+	}
+
+	shortest, _ := lookupSolver("SHORTEST")
+	tests := []struct {
+		name      string
+		opts      SolverOptions
+		wantSteps []int // per pohon, terurut dari yang terbaik
+		wantDepth int
+	}{
+		{"steps", SolverOptions{MaxRecipes: 3}, []int{3, 4, 5}, 3},
+		{"depth", SolverOptions{MaxRecipes: 1, Objective: objectiveDepth}, nil, 3},
+		// Dengan Mud dimiliki, Clay+Clay cukup dua langkah dan kedalaman 2
+		{"steps with inventory", SolverOptions{MaxRecipes: 1, Inventory: []string{"Mud"}}, []int{2}, 2},
+		{"depth with inventory", SolverOptions{MaxRecipes: 1, Objective: objectiveDepth, Inventory: []string{"Mud"}}, nil, 2},
+	}
+	for _, tt := range tests {
+		result := shortest.Solve(ctx, graph, "golem", tt.opts)
+		if len(result.Trees) == 0 {
+			t.Errorf("%s: no trees", tt.name)
+			continue
+		}
+		if tt.wantSteps != nil {
+			var got []int
+			for _, tree := range result.Trees {
+				got = append(got, distinctSteps(tree))
+			}
+			if !reflect.DeepEqual(got, tt.wantSteps) {
+				t.Errorf("%s: distinct steps = %v, want %v", tt.name, got, tt.wantSteps)
+			}
+		}
+		if got := treeDepth(result.Trees[0]); got != tt.wantDepth {
+			t.Errorf("%s: depth = %d, want %d", tt.name, got, tt.wantDepth)
+		}
+	}
+}
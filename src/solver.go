@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"encoding/binary"
+	"hash/fnv"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// SolverOptions berisi parameter pencarian yang diteruskan ke setiap solver
+type SolverOptions struct {
+	MaxRecipes int
+	Delay      int
+	Limits     SearchLimits
+	// Objective dipakai solver SHORTEST: "steps" (default) atau "depth"
+	Objective string
+	// Inventory adalah elemen yang sudah dimiliki pemain dan diperlakukan seperti elemen dasar
+	Inventory []string
+	// Deterministic menjalankan solver tanpa goroutine yang berlomba dan dengan urutan iterasi tetap,
+	// jadi input dan dataset yang sama selalu memberi pohon yang sama dengan urutan yang sama.
+	// Hasil hanya bisa berbeda jika pencarian terpotong timeout.
+	Deterministic bool
+	// Seed selain 0 mengacak urutan eksplorasi resep secara reproducible dan selalu mengaktifkan Deterministic
+	Seed int64
+}
+
+// searchOrder menentukan urutan solver mengunjungi elemen dan resep
+type searchOrder struct {
+	deterministic bool
+	seed          int64
+}
+
+func (o SolverOptions) order() searchOrder {
+	return searchOrder{deterministic: o.Deterministic || o.Seed != 0, seed: o.Seed}
+}
+
+// rank adalah posisi acak-tapi-tetap sebuah kunci untuk seed ini. Tidak memakai state bersama,
+// jadi hasilnya tidak bergantung pada urutan pemanggilan atau goroutine mana yang memanggil.
+func (o searchOrder) rank(key string) uint64 {
+	h := fnv.New64a()
+	binary.Write(h, binary.LittleEndian, o.seed)
+	h.Write([]byte(key))
+	return h.Sum64()
+}
+
+// recipes mengembalikan resep element dalam urutan eksplorasi: urutan dataset, atau diacak jika ada seed
+func (o searchOrder) recipes(element string, recipes [][]string) [][]string {
+	if o.seed == 0 || len(recipes) < 2 {
+		return recipes
+	}
+	type ranked struct {
+		recipe []string
+		rank   uint64
+	}
+	list := make([]ranked, len(recipes))
+	for i, r := range recipes {
+		list[i] = ranked{r, o.rank(element + "|" + strings.Join(r, "+"))}
+	}
+	sort.SliceStable(list, func(i, j int) bool { return list[i].rank < list[j].rank })
+	out := make([][]string, len(list))
+	for i, r := range list {
+		out[i] = r.recipe
+	}
+	return out
+}
+
+// names mengembalikan names (sudah terurut) atau salinannya yang diacak dengan seed
+func (o searchOrder) names(names []string) []string {
+	if o.seed == 0 {
+		return names
+	}
+	out := append([]string{}, names...)
+	sort.SliceStable(out, func(i, j int) bool { return o.rank(out[i]) < o.rank(out[j]) })
+	return out
+}
+
+// LeafSet berisi elemen yang tidak perlu dibuat lagi: elemen dasar ditambah inventory pemain
+type LeafSet map[string]bool
+
+// newLeafSet memakai Resolve untuk nama inventory, jadi alias dan bentuk jamak juga dikenali
+func (g *RecipeGraph) newLeafSet(inventory []string) LeafSet {
+	leaves := make(LeafSet, len(basicElements)+len(inventory))
+	for _, b := range basicElements {
+		leaves[b] = true
+	}
+	for _, name := range inventory {
+		if name, _ = g.Resolve(name); name != "" {
+			leaves[name] = true
+		}
+	}
+	return leaves
+}
+
+func (l LeafSet) Has(name string) bool {
+	return l[canonicalName(name)]
+}
+
+// Solver adalah algoritma pencarian resep yang bisa dipilih lewat field "algorithm"
+type Solver interface {
+	// Name adalah nilai "algorithm" yang dikirim client, misalnya "BFS"
+	Name() string
+	// Label dipakai untuk pesan status ke client
+	Label() string
+	// Solve dan SolveLive harus berhenti secepatnya saat ctx selesai
+	// dan mengembalikan resep yang sudah ditemukan sejauh ini
+	Solve(ctx context.Context, graph *RecipeGraph, target string, opts SolverOptions) SearchResult
+	SolveLive(ctx context.Context, graph *RecipeGraph, target string, opts SolverOptions, conn *websocket.Conn) SearchResult
+}
+
+var solverRegistry = make(map[string]Solver)
+
+// registerSolver mendaftarkan solver baru; dipanggil dari init() di file algoritma
+func registerSolver(s Solver) {
+	key := strings.ToUpper(s.Name())
+	if _, exists := solverRegistry[key]; exists {
+		panic("solver already registered: " + key)
+	}
+	solverRegistry[key] = s
+}
+
+func lookupSolver(name string) (Solver, bool) {
+	s, ok := solverRegistry[strings.ToUpper(strings.TrimSpace(name))]
+	return s, ok
+}
+
+func solverNames() []string {
+	names := make([]string, 0, len(solverRegistry))
+	for _, s := range solverRegistry {
+		names = append(names, s.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+// sleepCtx menunggu selama d atau sampai ctx selesai; mengembalikan false jika dibatalkan
+func sleepCtx(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// testElements adalah dataset kecil yang bisa dihitung dengan tangan. Tier diisi oleh newTestGraph.
+var testElements = []Element{
+	{Name: "Air"}, {Name: "Earth"}, {Name: "Fire"}, {Name: "Water"},
+	{Name: "Mud", Recipes: [][]string{{"Earth", "Water"}}},
+	{Name: "Lava", Recipes: [][]string{{"Earth", "Fire"}}},
+	{Name: "Stone", Recipes: [][]string{{"Lava", "Air"}, {"Mud", "Fire"}}},
+	{Name: "Brick", Recipes: [][]string{{"Mud", "Fire"}, {"Stone", "Mud"}}},
+	{Name: "Wall", Recipes: [][]string{{"Brick", "Brick"}, {"Stone", "Stone"}}},
+}
+
+// newTestGraph membangun RecipeGraph dari salinan elements dengan tier minimal
+func newTestGraph(t *testing.T, elements []Element) *RecipeGraph {
+	t.Helper()
+	elements = normalizeElements(append([]Element{}, elements...), nil)
+	recomputeTiers(elements)
+	g := newRecipeGraph(elements, nil)
+	if err := g.check(); err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+// chainElements membuat n elemen yang tidak berhubungan dengan testElements: Chain k dibuat dari
+// Chain k-1 dengan dua resep, jadi jumlah pohonnya berlipat dua di setiap tingkat
+func chainElements(n int) []Element {
+	elements := []Element{{Name: "Chain 0", Recipes: [][]string{{"Air", "Air"}, {"Air", "Water"}}}}
+	for k := 1; k < n; k++ {
+		prev := fmt.Sprintf("Chain %d", k-1)
+		elements = append(elements, Element{
+			Name:    fmt.Sprintf("Chain %d", k),
+			Recipes: [][]string{{prev, "Air"}, {prev, "Water"}},
+		})
+	}
+	return elements
+}
+
+// braidElements membuat dua rantai Braid dan Strand yang saling bergantung: setiap pohon Braid n-1 baru
+// lengkap setelah sekitar 2n langkah, dan jumlah rencana setengah jadi berlipat di setiap langkah
+func braidElements(n int) []Element {
+	elements := []Element{
+		{Name: "Braid 0", Recipes: [][]string{{"Air", "Water"}, {"Air", "Fire"}}},
+		{Name: "Strand 0", Recipes: [][]string{{"Earth", "Water"}, {"Earth", "Fire"}}},
+	}
+	for k := 1; k < n; k++ {
+		braid, strand := fmt.Sprintf("Braid %d", k-1), fmt.Sprintf("Strand %d", k-1)
+		elements = append(elements,
+			Element{Name: fmt.Sprintf("Braid %d", k), Recipes: [][]string{{braid, strand}, {braid, "Air"}}},
+			Element{Name: fmt.Sprintf("Strand %d", k), Recipes: [][]string{{braid, strand}, {strand, "Earth"}}},
+		)
+	}
+	return elements
+}
+
+// TestBidirectionalStopsWhenExhausted memastikan BID berhenti begitu kedua arah tidak bisa diperluas,
+// bukan berputar sampai timeout karena terus membangun pohon elemen lain
+func TestBidirectionalStopsWhenExhausted(t *testing.T) {
+	graph := newTestGraph(t, append(append([]Element{}, testElements...), chainElements(40)...))
+	solver, _ := lookupSolver("BID")
+
+	for _, target := range []string{"mud", "brick", "wall"} {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		start := time.Now()
+		result := solver.Solve(ctx, graph, target, SolverOptions{MaxRecipes: 50, Limits: SearchLimits{Timeout: 5 * time.Second, MaxDepth: serverLimits.MaxDepth}})
+		elapsed := time.Since(start)
+		cancel()
+
+		if elapsed > time.Second {
+			t.Errorf("%s: search took %v, want it to finish on its own", target, elapsed)
+		}
+		// Pencarian yang habis dengan sendirinya tidak terpotong oleh batas apa pun
+		if result.TruncatedBy != "" {
+			t.Errorf("%s: truncatedBy = %q, want \"\"", target, result.TruncatedBy)
+		}
+		if len(result.Trees) == 0 {
+			t.Errorf("%s: no trees found", target)
+		}
+	}
+
+	// Wall butuh tiga layer maju, jadi maxDepth 2 benar-benar memotong pencarian
+	result := solver.Solve(context.Background(), graph, "wall", SolverOptions{MaxRecipes: 50, Limits: SearchLimits{MaxDepth: 2}})
+	if result.TruncatedBy != truncatedByMaxDepth {
+		t.Errorf("wall with maxDepth 2: truncatedBy = %q, want %q", result.TruncatedBy, truncatedByMaxDepth)
+	}
+}
+
+// TestSolversDeterministic menjalankan setiap solver yang terdaftar beberapa kali dengan input yang sama.
+// Test regresi dan share-link bergantung pada pohon yang sama dengan urutan yang sama. Chain 9 punya
+// ratusan pohon, cukup banyak untuk membuat worker BFS dan BID berlomba jika mode deterministic rusak.
+func TestSolversDeterministic(t *testing.T) {
+	graph := newTestGraph(t, append(append([]Element{}, golemElements...), chainElements(10)...))
+	ctx := context.Background()
+
+	for _, name := range solverNames() {
+		solver, _ := lookupSolver(name)
+		for _, target := range []string{"golem", "wall", "chain 9"} {
+			for _, opts := range []SolverOptions{
+				{MaxRecipes: 20, Deterministic: true},
+				{MaxRecipes: 20, Seed: 42},
+			} {
+				first := solver.Solve(ctx, graph, target, opts)
+				if len(first.Trees) == 0 {
+					t.Errorf("%s %s seed %d: no trees", name, target, opts.Seed)
+					continue
+				}
+				for run := 0; run < 10; run++ {
+					again := solver.Solve(ctx, graph, target, opts)
+					if !reflect.DeepEqual(again.Trees, first.Trees) {
+						t.Errorf("%s %s seed %d: run %d returned different trees\n got: %+v\nwant: %+v", name, target, opts.Seed, run+2, again.Trees, first.Trees)
+						break
+					}
+				}
+			}
+		}
+	}
+}
+
+// TestBFSParallelFindsEveryTree membandingkan worker BFS paralel dengan BFS sekuensial. Worker tidak boleh
+// meninggalkan slot hasil kosong, dan tidak boleh berhenti hanya karena queue sesaat kosong selagi worker
+// lain masih memperluas item.
+func TestBFSParallelFindsEveryTree(t *testing.T) {
+	graph := newTestGraph(t, append(append([]Element{}, testElements...), chainElements(10)...))
+	solver, _ := lookupSolver("BFS")
+	ctx := context.Background()
+
+	for _, target := range []string{"wall", "chain 9"} {
+		want := solver.Solve(ctx, graph, target, SolverOptions{MaxRecipes: 5000, Deterministic: true})
+		wantKeys := make(map[string]bool)
+		for _, tree := range want.Trees {
+			wantKeys[canonicalizeTree(tree)] = true
+		}
+
+		for run := 0; run < 5; run++ {
+			got := solver.Solve(ctx, graph, target, SolverOptions{MaxRecipes: 5000})
+			if len(got.Trees) != len(want.Trees) {
+				t.Errorf("%s run %d: %d trees, want %d", target, run, len(got.Trees), len(want.Trees))
+				break
+			}
+			for _, tree := range got.Trees {
+				if !wantKeys[canonicalizeTree(tree)] {
+					t.Errorf("%s run %d: unexpected tree %+v", target, run, tree)
+					break
+				}
+			}
+		}
+	}
+}
+
+// TestSolversStopAtInventory: elemen inventory yang bukan elemen dasar menjadi daun di setiap solver.
+// Setiap resep Brick butuh Mud, jadi semua pohonnya harus memuat Mud tanpa resep Earth + Water di bawahnya.
+func TestSolversStopAtInventory(t *testing.T) {
+	graph := newTestGraph(t, testElements)
+	ctx := context.Background()
+
+	// hasLeaf melaporkan apakah name muncul di tree, dan apakah setiap kemunculannya adalah daun
+	var hasLeaf func(tree TreeNode, name string) (found, leaf bool)
+	hasLeaf = func(tree TreeNode, name string) (found, leaf bool) {
+		if tree.Name == name {
+			return true, len(tree.Children) == 0
+		}
+		leaf = true
+		for _, c := range tree.Children {
+			f, l := hasLeaf(c, name)
+			found = found || f
+			leaf = leaf && l
+		}
+		return found, leaf
+	}
+
+	for _, name := range solverNames() {
+		solver, _ := lookupSolver(name)
+		result := solver.Solve(ctx, graph, "brick", SolverOptions{MaxRecipes: 10, Inventory: []string{"mud"}, Deterministic: true})
+		if len(result.Trees) == 0 {
+			t.Errorf("%s: no trees", name)
+			continue
+		}
+		for _, tree := range result.Trees {
+			found, leaf := hasLeaf(tree, "Mud")
+			if !found || !leaf {
+				t.Errorf("%s: tree %+v does not stop at inventory element Mud", name, tree)
+			}
+		}
+	}
+}
+
+// TestBidirectionalTreeLimit: batas pohon per elemen BID hanya dilaporkan sebagai maxQueue jika
+// maxQueue client yang memotong; batas dari maxRecipes*1000 atau bawaan server punya alasan sendiri
+func TestBidirectionalTreeLimit(t *testing.T) {
+	tests := []struct {
+		opts       SolverOptions
+		wantLimit  int
+		wantReason string
+	}{
+		{SolverOptions{MaxRecipes: 1}, 1000, truncatedByTreeLimit},
+		{SolverOptions{MaxRecipes: 100}, bidMaxRecipesPerElmt, truncatedByTreeLimit},
+		{SolverOptions{MaxRecipes: 100, Limits: SearchLimits{MaxQueue: 50}}, 50, truncatedByMaxQueue},
+		{SolverOptions{MaxRecipes: 100, Limits: SearchLimits{MaxQueue: 50000}}, 50000, truncatedByMaxQueue},
+		{SolverOptions{MaxRecipes: 1, Limits: SearchLimits{MaxQueue: 50000}}, 1000, truncatedByTreeLimit},
+	}
+	for _, tt := range tests {
+		limit, reason := bidTreeLimit(tt.opts)
+		if limit != tt.wantLimit || reason != tt.wantReason {
+			t.Errorf("bidTreeLimit(%+v) = %d, %q, want %d, %q", tt.opts, limit, reason, tt.wantLimit, tt.wantReason)
+		}
+	}
+
+	// Chain 13 punya 16384 pohon; maxQueue 10 memotong setiap elemen sebelum maxRecipes tercapai
+	graph := newTestGraph(t, append(append([]Element{}, testElements...), chainElements(14)...))
+	solver, _ := lookupSolver("BID")
+	result := solver.Solve(context.Background(), graph, "chain 13", SolverOptions{MaxRecipes: 5000, Limits: SearchLimits{MaxQueue: 10}, Deterministic: true})
+	if len(result.Trees) != 10 || result.TruncatedBy != truncatedByMaxQueue {
+		t.Errorf("chain 13 with maxQueue 10: %d trees, truncatedBy %q, want 10 and %q", len(result.Trees), result.TruncatedBy, truncatedByMaxQueue)
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import "sort"
+
+// TierChange mencatat elemen yang tier-nya berubah setelah dihitung ulang
+type TierChange struct {
+	Name string `json:"name"`
+	Old  int    `json:"old"`
+	New  int    `json:"new"`
+}
+
+// minimalTiers menghitung kedalaman derivasi terpendek setiap elemen: elemen dasar tier 0, elemen lain
+// 1 + tier bahan tertinggi pada resep termurahnya. Perhitungan berjalan per putaran (titik tetap):
+// pada putaran k hanya tier dari putaran sebelumnya yang dipakai, jadi elemen yang pertama kali bisa
+// dibuat pada putaran k pasti bertier k dan hasilnya tidak bergantung pada urutan iterasi map.
+// Elemen yang tidak bisa diturunkan dari elemen dasar tidak ada di hasil.
+func minimalTiers(elements []Element) map[string]int {
+	recipes := make(map[string][][]string, len(elements))
+	for _, e := range elements {
+		name := canonicalName(e.Name)
+		for _, recipe := range e.Recipes {
+			if len(recipe) != 2 {
+				continue
+			}
+			a := canonicalName(recipe[0])
+			b := canonicalName(recipe[1])
+			recipes[name] = append(recipes[name], []string{a, b})
+		}
+	}
+
+	tiers := make(map[string]int, len(elements))
+	for _, b := range basicElements {
+		tiers[b] = 0
+	}
+
+	for tier := 1; ; tier++ {
+		var reached []string
+		for name, list := range recipes {
+			if _, done := tiers[name]; done {
+				continue
+			}
+			for _, recipe := range list {
+				ta, okA := tiers[recipe[0]]
+				tb, okB := tiers[recipe[1]]
+				if okA && okB && ta < tier && tb < tier {
+					reached = append(reached, name)
+					break
+				}
+			}
+		}
+		if len(reached) == 0 {
+			return tiers
+		}
+		for _, name := range reached {
+			tiers[name] = tier
+		}
+	}
+}
+
+// recomputeTiers mengisi ulang Tier semua elemen dengan minimalTiers. Elemen tanpa resep mendapat
+// tierNoRecipes dan elemen yang resepnya tidak pernah bisa diselesaikan mendapat tierUnresolvable.
+// Hasilnya adalah daftar elemen yang tier-nya berubah, terurut berdasarkan nama.
+func recomputeTiers(elements []Element) []TierChange {
+	tiers := minimalTiers(elements)
+
+	changes := []TierChange{}
+	for i := range elements {
+		e := &elements[i]
+		tier, ok := tiers[canonicalName(e.Name)]
+		switch {
+		case ok:
+		case len(e.Recipes) == 0:
+			tier = tierNoRecipes
+		default:
+			tier = tierUnresolvable
+		}
+		if tier != e.Tier {
+			changes = append(changes, TierChange{Name: e.Name, Old: e.Tier, New: tier})
+			e.Tier = tier
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return canonicalName(changes[i].Name) < canonicalName(changes[j].Name)
+	})
+	return changes
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMinimalTiers(t *testing.T) {
+	elements := []Element{
+		{Name: "Air"}, {Name: "Earth"}, {Name: "Fire"}, {Name: "Water"},
+		{Name: "Mud", Recipes: [][]string{{"Earth", "Water"}}},
+		{Name: "Clay", Recipes: [][]string{{"Mud", "Fire"}}},
+		{Name: "Brick", Recipes: [][]string{{"Clay", "Fire"}}},
+		// Resep pertama lewat Brick (tier 3), resep kedua langsung dari elemen dasar
+		{Name: "Wall", Recipes: [][]string{{"Brick", "Brick"}, {"Earth", "Air"}}},
+		{Name: "House", Recipes: [][]string{{"Wall", "Brick"}}},
+		// Bahan tidak ada di dataset, atau hanya bisa dibuat dari dirinya sendiri
+		{Name: "Dragon", Recipes: [][]string{{"Fire", "Legend"}}},
+		{Name: "Phoenix", Recipes: [][]string{{"Phoenix", "Fire"}}},
+		{Name: "Time"},
+	}
+
+	want := map[string]int{
+		"air": 0, "earth": 0, "fire": 0, "water": 0,
+		"mud": 1, "clay": 2, "brick": 3,
+		"wall":  1,
+		"house": 4,
+	}
+	// Tier tidak boleh bergantung pada urutan iterasi map, jadi hasil beberapa kali pemanggilan harus sama
+	for i := 0; i < 20; i++ {
+		if got := minimalTiers(elements); !reflect.DeepEqual(got, want) {
+			t.Fatalf("run %d: minimalTiers = %v, want %v", i, got, want)
+		}
+	}
+
+	// Urutan elemen di file juga tidak berpengaruh
+	reversed := make([]Element, len(elements))
+	for i, e := range elements {
+		reversed[len(elements)-1-i] = e
+	}
+	if got := minimalTiers(reversed); !reflect.DeepEqual(got, want) {
+		t.Errorf("reversed input: minimalTiers = %v, want %v", got, want)
+	}
+}
+
+func TestRecomputeTiers(t *testing.T) {
+	elements := []Element{
+		{Name: "Air"}, {Name: "Earth"}, {Name: "Fire"}, {Name: "Water"},
+		{Name: "Mud", Recipes: [][]string{{"Earth", "Water"}}, Tier: 1},
+		{Name: "Wall", Recipes: [][]string{{"Mud", "Mud"}, {"Earth", "Air"}}, Tier: 2},
+		{Name: "Dragon", Recipes: [][]string{{"Fire", "Legend"}}, Tier: 5},
+		{Name: "Time", Tier: 3},
+	}
+
+	changes := recomputeTiers(elements)
+
+	wantTiers := map[string]int{
+		"Air": 0, "Earth": 0, "Fire": 0, "Water": 0,
+		"Mud":    1,
+		"Wall":   1,
+		"Dragon": tierUnresolvable,
+		"Time":   tierNoRecipes,
+	}
+	for _, e := range elements {
+		if e.Tier != wantTiers[e.Name] {
+			t.Errorf("%s: tier %d, want %d", e.Name, e.Tier, wantTiers[e.Name])
+		}
+	}
+
+	wantChanges := []TierChange{
+		{Name: "Dragon", Old: 5, New: tierUnresolvable},
+		{Name: "Time", Old: 3, New: tierNoRecipes},
+		{Name: "Wall", Old: 2, New: 1},
+	}
+	if !reflect.DeepEqual(changes, wantChanges) {
+		t.Errorf("changes = %+v, want %+v", changes, wantChanges)
+	}
+}
@@ -1,11 +1,7 @@
 package main
 
-import (
-	"strings"
-)
-
 func buildRecipeTree(elementName string, recipeSteps map[string][]string, elementMap map[string]Element, visitedInThisTree map[string]bool, memoizedTrees map[string]TreeNode) TreeNode {
-	elementName = strings.ToLower(elementName)
+	elementName = canonicalName(elementName)
 
 	if !visitedInThisTree[elementName] {
 		if cachedNode, found := memoizedTrees[elementName]; found {
@@ -13,7 +9,7 @@ func buildRecipeTree(elementName string, recipeSteps map[string][]string, elemen
 		}
 	}
 
-	node := TreeNode{Name: capitalize(elementName)}
+	node := TreeNode{Name: displayName(elementMap, elementName)}
 	if isBasicElement(elementName) || visitedInThisTree[elementName] {
 		if isBasicElement(elementName) && !visitedInThisTree[elementName] {
 			memoizedTrees[elementName] = node
@@ -26,8 +22,8 @@ func buildRecipeTree(elementName string, recipeSteps map[string][]string, elemen
 	parentsToUse, partOfThisSpecificRecipe := recipeSteps[elementName]
 
 	if partOfThisSpecificRecipe && len(parentsToUse) == 2 {
-		parent1 := strings.ToLower(parentsToUse[0])
-		parent2 := strings.ToLower(parentsToUse[1])
+		parent1 := canonicalName(parentsToUse[0])
+		parent2 := canonicalName(parentsToUse[1])
 
 		childNode1 := buildRecipeTree(parent1, recipeSteps, elementMap, visitedInThisTree, memoizedTrees)
 		childNode2 := buildRecipeTree(parent2, recipeSteps, elementMap, visitedInThisTree, memoizedTrees)
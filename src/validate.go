@@ -0,0 +1,193 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+const (
+	severityError   = "error"
+	severityWarning = "warning"
+)
+
+// Tier pengganti yang ditulis recomputeTiers untuk elemen yang tier-nya tidak bisa dihitung
+const (
+	tierUnresolvable = 998
+	tierNoRecipes    = 999
+)
+
+// ValidationIssue adalah satu masalah pada dataset. Code stabil dan bisa dipakai untuk filter otomatis.
+type ValidationIssue struct {
+	Severity string `json:"severity"`
+	Code     string `json:"code"`
+	Element  string `json:"element,omitempty"`
+	Message  string `json:"message"`
+}
+
+// ValidationReport adalah hasil validateElements. Errors berarti dataset rusak dan tidak boleh dipakai;
+// warnings adalah data yang janggal tetapi masih bisa dicari.
+type ValidationReport struct {
+	Elements int `json:"elements"`
+	Errors   int `json:"errors"`
+	Warnings int `json:"warnings"`
+	// Ignored adalah jumlah issue yang dibuang oleh ignore
+	Ignored int               `json:"ignored,omitempty"`
+	Issues  []ValidationIssue `json:"issues"`
+}
+
+// validationCodes adalah semua Code yang bisa dihasilkan validateElements
+var validationCodes = []string{
+	"empty-name", "duplicate-name", "missing-basic",
+	"missing-tier", "placeholder-tier", "basic-tier", "wrong-tier",
+	"no-recipes", "malformed-recipe", "self-recipe", "unknown-ingredient", "no-lower-tier-recipe",
+}
+
+// parseIgnoreCodes membaca nilai flag -ignore, daftar code dipisah koma. Code yang tidak dikenal
+// ditolak supaya salah ketik tidak diam-diam meloloskan dataset.
+func parseIgnoreCodes(s string) ([]string, error) {
+	var codes []string
+	for _, code := range strings.Split(s, ",") {
+		code = strings.TrimSpace(code)
+		if code == "" {
+			continue
+		}
+		if !slices.Contains(validationCodes, code) {
+			return nil, fmt.Errorf("unknown validation code %q (available: %s)", code, strings.Join(validationCodes, ", "))
+		}
+		codes = append(codes, code)
+	}
+	return codes, nil
+}
+
+// ignore mengembalikan salinan laporan tanpa issue dengan code tersebut, untuk kejanggalan wiki yang
+// memang diterima secara eksplisit. Jumlah yang dibuang tetap dicatat di Ignored.
+func (r ValidationReport) ignore(codes []string) ValidationReport {
+	if len(codes) == 0 {
+		return r
+	}
+	out := ValidationReport{Elements: r.Elements, Ignored: r.Ignored, Issues: []ValidationIssue{}}
+	for _, issue := range r.Issues {
+		switch {
+		case slices.Contains(codes, issue.Code):
+			out.Ignored++
+		case issue.Severity == severityError:
+			out.Errors++
+			out.Issues = append(out.Issues, issue)
+		default:
+			out.Warnings++
+			out.Issues = append(out.Issues, issue)
+		}
+	}
+	return out
+}
+
+func (r *ValidationReport) add(severity, code, element, format string, args ...interface{}) {
+	r.Issues = append(r.Issues, ValidationIssue{
+		Severity: severity,
+		Code:     code,
+		Element:  element,
+		Message:  fmt.Sprintf(format, args...),
+	})
+	if severity == severityError {
+		r.Errors++
+	} else {
+		r.Warnings++
+	}
+}
+
+// OK bernilai true jika tidak ada error
+func (r ValidationReport) OK() bool {
+	return r.Errors == 0
+}
+
+// validateElements memeriksa invariant dataset sebelum dipakai solver. Pemeriksaan dilakukan
+// pada daftar elemen mentah supaya nama ganda yang hanya beda huruf besar/kecil masih terlihat.
+func validateElements(elements []Element) ValidationReport {
+	report := ValidationReport{Elements: len(elements), Issues: []ValidationIssue{}}
+
+	byName := make(map[string]Element, len(elements))
+	for _, e := range elements {
+		name := canonicalName(e.Name)
+		if name == "" {
+			report.add(severityError, "empty-name", "", "element with an empty name")
+			continue
+		}
+		if prev, ok := byName[name]; ok {
+			report.add(severityError, "duplicate-name", e.Name, "%q and %q differ only by case or spacing", prev.Name, e.Name)
+			continue
+		}
+		byName[name] = e
+	}
+
+	for _, b := range basicElements {
+		if _, ok := byName[b]; !ok {
+			report.add(severityError, "missing-basic", capitalize(b), "basic element %s is missing", capitalize(b))
+		}
+	}
+
+	tiers := minimalTiers(elements)
+	names := make([]string, 0, len(byName))
+	for name := range byName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		e := byName[name]
+		basic := isBasicElement(name)
+
+		switch {
+		case e.Tier < 0:
+			report.add(severityError, "missing-tier", e.Name, "tier %d, tiers were never computed (run \"tiers -write\")", e.Tier)
+		case e.Tier == tierUnresolvable || e.Tier == tierNoRecipes:
+			report.add(severityError, "placeholder-tier", e.Name, "placeholder tier %d, the element cannot be derived from the basic elements", e.Tier)
+		case basic && e.Tier != 0:
+			report.add(severityError, "basic-tier", e.Name, "basic element has tier %d instead of 0", e.Tier)
+		default:
+			if tier, ok := tiers[name]; ok && tier != e.Tier {
+				report.add(severityWarning, "wrong-tier", e.Name, "tier %d, the shortest derivation gives %d (run \"tiers -write\")", e.Tier, tier)
+			}
+		}
+
+		if len(e.Recipes) == 0 {
+			if !basic {
+				report.add(severityWarning, "no-recipes", e.Name, "no recipes, the element cannot be crafted")
+			}
+			continue
+		}
+
+		lowerTier := false
+		for _, recipe := range e.Recipes {
+			if len(recipe) != 2 {
+				report.add(severityError, "malformed-recipe", e.Name, "recipe %v does not have exactly two ingredients", recipe)
+				continue
+			}
+			known := true
+			maxTier := 0
+			for _, ing := range recipe {
+				ingName := canonicalName(ing)
+				if ingName == name {
+					report.add(severityError, "self-recipe", e.Name, "recipe %s + %s uses the element itself", recipe[0], recipe[1])
+					known = false
+					break
+				}
+				ingElem, ok := byName[ingName]
+				if !ok {
+					report.add(severityError, "unknown-ingredient", e.Name, "recipe %s + %s uses unknown element %q", recipe[0], recipe[1], ing)
+					known = false
+					continue
+				}
+				maxTier = max(maxTier, ingElem.Tier)
+			}
+			if known && maxTier < e.Tier {
+				lowerTier = true
+			}
+		}
+		if !basic && !lowerTier {
+			report.add(severityWarning, "no-lower-tier-recipe", e.Name, "no recipe with lower-tier ingredients, solvers will never reach this element")
+		}
+	}
+	return report
+}
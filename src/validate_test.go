@@ -0,0 +1,76 @@
+package main
+
+import "testing"
+
+// wikiQuirks adalah code yang memang muncul di data wiki (halaman fanon, resep dari pack lain, elemen
+// yang tidak bisa dibuat) dan di-ignore secara eksplisit oleh data repo sendiri, seperti di README
+var wikiQuirks = []string{"unknown-ingredient", "self-recipe", "placeholder-tier"}
+
+// TestShippedDatasetValid menjaga data/elements.json tidak punya error selain wikiQuirks, supaya
+// "serve -strict -ignore" dan "validate -ignore" bisa dipakai dengan data repo sendiri
+func TestShippedDatasetValid(t *testing.T) {
+	graph, err := loadRecipeGraph("data/elements.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := graph.Validation.ignore(wikiQuirks)
+	if report.Ignored == 0 {
+		t.Errorf("no issues ignored, drop the codes that no longer occur from wikiQuirks and the README")
+	}
+	for _, issue := range report.Issues {
+		if issue.Severity == severityError {
+			t.Errorf("[%s] %s: %s", issue.Code, issue.Element, issue.Message)
+		}
+	}
+}
+
+func TestValidateSeverities(t *testing.T) {
+	elements := append(append([]Element{}, testElements...),
+		Element{Name: "Unset", Recipes: [][]string{{"Earth", "Air"}}, Tier: -1},
+		Element{Name: "Fanon", Recipes: [][]string{{"Earth", "OvalFanon"}}, Tier: tierUnresolvable},
+		Element{Name: "Echo", Recipes: [][]string{{"Echo", "Air"}, {"Air", "Air"}}, Tier: 1},
+	)
+	recomputeTiers(elements[:len(testElements)])
+
+	want := map[string]string{
+		"missing-tier":       severityError,
+		"unknown-ingredient": severityError,
+		"placeholder-tier":   severityError,
+		"self-recipe":        severityError,
+	}
+	got := make(map[string]string)
+	for _, issue := range validateElements(elements).Issues {
+		got[issue.Code] = issue.Severity
+	}
+	for code, severity := range want {
+		if got[code] != severity {
+			t.Errorf("%s: severity %q, want %q", code, got[code], severity)
+		}
+	}
+}
+
+func TestValidateIgnore(t *testing.T) {
+	elements := append(append([]Element{}, testElements...),
+		Element{Name: "Fanon", Recipes: [][]string{{"Earth", "OvalFanon"}}, Tier: tierUnresolvable},
+	)
+	recomputeTiers(elements[:len(testElements)])
+	report := validateElements(elements)
+	if report.OK() {
+		t.Fatalf("report without -ignore is OK, want unknown-ingredient and placeholder-tier errors")
+	}
+
+	ignored := report.ignore([]string{"unknown-ingredient", "placeholder-tier"})
+	if !ignored.OK() || ignored.Ignored != 2 {
+		t.Errorf("after ignore: %d errors, %d ignored, want 0 and 2: %+v", ignored.Errors, ignored.Ignored, ignored.Issues)
+	}
+	if ignored.Warnings != report.Warnings {
+		t.Errorf("after ignore: %d warnings, want the original %d", ignored.Warnings, report.Warnings)
+	}
+
+	if _, err := parseIgnoreCodes("unknown-ingredient, self-recipe"); err != nil {
+		t.Error(err)
+	}
+	if _, err := parseIgnoreCodes("unknown-ingredients"); err == nil {
+		t.Error("parseIgnoreCodes accepted a misspelled code")
+	}
+}